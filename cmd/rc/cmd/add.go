@@ -3,8 +3,10 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/shaneoxm/recall/internal/dateparse"
 	"github.com/shaneoxm/recall/internal/protocol"
 	"github.com/spf13/cobra"
 )
@@ -16,10 +18,12 @@ var addCmd = &cobra.Command{
 
 Examples:
   rc add "Call mom"
-  rc add "Call mom" --due tomorrow --note "Birthday next week"
+  rc add "Call mom" --due "tomorrow at 6pm" --note "Birthday next week"
   rc add "Review PR" --due monday --link "https://github.com/..." --tag work
-  rc add "Pay rent" --due friday --priority high`,
-	Args: cobra.ExactArgs(1),
+  rc add "Pay rent" --due friday --priority high
+  rc add "Pay rent" --due 2024-02-01 --repeat monthly
+  rc add "Standup notes" --due tomorrow --repeat weekdays`,
+	Args: exactArgs(1),
 	RunE: runAdd,
 }
 
@@ -29,16 +33,18 @@ var (
 	addLinks    []string
 	addTags     []string
 	addPriority string
+	addRepeat   string
 )
 
 func init() {
 	rootCmd.AddCommand(addCmd)
 
-	addCmd.Flags().StringVarP(&addDue, "due", "d", "", "due date (e.g., tomorrow, monday, 2024-01-15)")
+	addCmd.Flags().StringVarP(&addDue, "due", "d", "", "due date (e.g., tomorrow 3pm, next friday, in 2 hours, 2024-01-15)")
 	addCmd.Flags().StringVarP(&addNote, "note", "n", "", "note or instructions")
 	addCmd.Flags().StringSliceVarP(&addLinks, "link", "l", nil, "links (can be specified multiple times)")
 	addCmd.Flags().StringSliceVarP(&addTags, "tag", "t", nil, "tags (can be specified multiple times)")
 	addCmd.Flags().StringVarP(&addPriority, "priority", "p", "", "priority (low, medium, high)")
+	addCmd.Flags().StringVarP(&addRepeat, "repeat", "r", "", "repeat (daily, weekly, monthly, yearly, weekdays, \"every 2 weeks\", \"every mon, fri\" or an RRULE)")
 }
 
 func runAdd(cmd *cobra.Command, args []string) error {
@@ -58,18 +64,29 @@ func runAdd(cmd *cobra.Command, args []string) error {
 	}
 
 	if addPriority != "" {
-		priority := parsePriority(addPriority)
+		priority, err := parsePriority(addPriority)
+		if err != nil {
+			return invalidArgument(err)
+		}
 		reminder.SetPriority(priority)
 	}
 
 	if addDue != "" {
 		due, err := parseDue(addDue)
 		if err != nil {
-			return fmt.Errorf("invalid due date: %w", err)
+			return invalidArgument(fmt.Errorf("invalid due date: %w", err))
 		}
 		reminder.SetDue(due)
 	}
 
+	if addRepeat != "" {
+		rec, err := protocol.ParseRecurrence(addRepeat)
+		if err != nil {
+			return invalidArgument(fmt.Errorf("invalid repeat: %w", err))
+		}
+		reminder.SetRecurrence(rec)
+	}
+
 	s, err := getStore()
 	if err != nil {
 		return fmt.Errorf("initializing store: %w", err)
@@ -79,6 +96,10 @@ func runAdd(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("saving reminder: %w", err)
 	}
 
+	if structuredOutput() {
+		return render(reminder)
+	}
+
 	fmt.Printf("Created reminder: %s (ID: %s)\n", reminder.Title, reminder.ID)
 	if reminder.Due != nil {
 		fmt.Printf("  Due: %s\n", reminder.Due.Format("Mon Jan 2, 2006 3:04 PM"))
@@ -92,73 +113,43 @@ func runAdd(cmd *cobra.Command, args []string) error {
 	if len(reminder.Tags) > 0 {
 		fmt.Printf("  Tags: %v\n", reminder.Tags)
 	}
+	if reminder.Recurrence != nil {
+		fmt.Printf("  Repeats: %s\n", reminder.Recurrence.Describe())
+	}
 
 	return nil
 }
 
-func parsePriority(s string) int {
-	switch s {
+func parsePriority(s string) (int, error) {
+	switch strings.ToLower(s) {
+	case "none", "0":
+		return 0, nil
 	case "low", "1":
-		return 1
+		return 1, nil
 	case "medium", "med", "2":
-		return 2
+		return 2, nil
 	case "high", "3":
-		return 3
+		return 3, nil
 	default:
-		return 0
+		return 0, fmt.Errorf("invalid priority: %s (use: none, low, medium, high)", s)
 	}
 }
 
-func parseDue(s string) (time.Time, error) {
-	now := time.Now()
-	today := time.Date(now.Year(), now.Month(), now.Day(), 9, 0, 0, 0, now.Location())
-
-	switch s {
-	case "today":
-		return today, nil
-	case "tomorrow":
-		return today.AddDate(0, 0, 1), nil
-	case "monday", "mon":
-		return nextWeekday(today, time.Monday), nil
-	case "tuesday", "tue":
-		return nextWeekday(today, time.Tuesday), nil
-	case "wednesday", "wed":
-		return nextWeekday(today, time.Wednesday), nil
-	case "thursday", "thu":
-		return nextWeekday(today, time.Thursday), nil
-	case "friday", "fri":
-		return nextWeekday(today, time.Friday), nil
-	case "saturday", "sat":
-		return nextWeekday(today, time.Saturday), nil
-	case "sunday", "sun":
-		return nextWeekday(today, time.Sunday), nil
-	}
-
-	// Try parsing as date
-	formats := []string{
-		"2006-01-02",
-		"01/02/2006",
-		"Jan 2",
-		"January 2",
-	}
-
-	for _, format := range formats {
-		if t, err := time.Parse(format, s); err == nil {
-			// If year wasn't specified, use current year
-			if t.Year() == 0 {
-				t = t.AddDate(now.Year(), 0, 0)
-			}
-			return time.Date(t.Year(), t.Month(), t.Day(), 9, 0, 0, 0, now.Location()), nil
-		}
+func priorityName(p int) string {
+	switch p {
+	case 1:
+		return "low"
+	case 2:
+		return "medium"
+	case 3:
+		return "high"
+	default:
+		return "none"
 	}
-
-	return time.Time{}, fmt.Errorf("could not parse date: %s", s)
 }
 
-func nextWeekday(from time.Time, weekday time.Weekday) time.Time {
-	days := int(weekday - from.Weekday())
-	if days <= 0 {
-		days += 7
-	}
-	return from.AddDate(0, 0, days)
+// parseDue parses a natural-language due date; see dateparse.Parser.Parse
+// for the supported forms.
+func parseDue(s string) (time.Time, error) {
+	return dateparse.Parse(s)
 }
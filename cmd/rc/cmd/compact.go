@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/shaneoxm/recall/internal/adapters/jsonl"
+	"github.com/shaneoxm/recall/internal/gitsync"
+	"github.com/spf13/cobra"
+)
+
+var compactCmd = &cobra.Command{
+	Use:   "compact",
+	Short: "Collapse the local reminders file to one line per reminder",
+	Long: `Rewrite ~/.recall/reminders.jsonl with one line per live reminder.
+
+Edits, completions and deletions are appended to the file as new lines, so
+it keeps growing. Compacting drops superseded versions, deleted reminders
+and their tombstones. With --archive, the dropped lines are appended to
+reminders.archive.jsonl next to the file first.
+
+Examples:
+  rc compact
+  rc compact --archive`,
+	Args: cobra.NoArgs,
+	RunE: runCompact,
+}
+
+var compactArchive bool
+
+func init() {
+	rootCmd.AddCommand(compactCmd)
+
+	compactCmd.Flags().BoolVar(&compactArchive, "archive", false, "keep dropped lines in reminders.archive.jsonl")
+}
+
+// compactOutput is the structured result of rc compact.
+type compactOutput struct {
+	*jsonl.CompactResult `yaml:",inline"`
+	Archive              string `json:"archive,omitempty" yaml:"archive,omitempty"`
+}
+
+func runCompact(cmd *cobra.Command, args []string) error {
+	s, err := getStore()
+	if err != nil {
+		return fmt.Errorf("initializing store: %w", err)
+	}
+	tracked, _ := s.(*gitsync.Store)
+	if tracked != nil {
+		s = tracked.Unwrap()
+	}
+	local, ok := s.(*jsonl.Store)
+	if !ok {
+		return invalidArgument(errors.New("compact only applies to the local backend"))
+	}
+
+	var archive string
+	if compactArchive {
+		archive = archivePath(local.Path())
+	}
+
+	// The rewrite and its commit happen under one lock, so no other rc
+	// process commits in between.
+	var result *jsonl.CompactResult
+	err = local.Locked(context.Background(), func(ctx context.Context) error {
+		var err error
+		if result, err = local.Compact(ctx, archive); err != nil {
+			return fmt.Errorf("compacting: %w", err)
+		}
+		if tracked == nil {
+			return nil
+		}
+		paths := []string{filepath.Base(local.Path())}
+		if result.Archived > 0 {
+			paths = append(paths, filepath.Base(archive))
+		}
+		if _, err := tracked.Repo().Commit(ctx, "compact", paths...); err != nil {
+			return fmt.Errorf("committing: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	out := compactOutput{CompactResult: result}
+	if result.Archived > 0 {
+		out.Archive = archive
+	}
+	if structuredOutput() {
+		return render(out)
+	}
+
+	fmt.Printf("Compacted %d lines to %d reminders.\n", result.Lines, result.Live)
+	if out.Archive != "" {
+		fmt.Printf("Archived %d lines to %s\n", result.Archived, out.Archive)
+	}
+	if result.Malformed > 0 {
+		fmt.Printf("Kept %d unreadable lines at the end of the file.\n", result.Malformed)
+	}
+	return nil
+}
+
+// archivePath returns the archive file for a reminders file:
+// reminders.jsonl -> reminders.archive.jsonl.
+func archivePath(path string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + ".archive" + ext
+}
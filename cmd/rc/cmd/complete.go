@@ -15,7 +15,7 @@ var completeCmd = &cobra.Command{
 Examples:
   rc complete 1768773271812-7727a989
   rc complete 1768773271812-7727a989 --backend apple`,
-	Args: cobra.ExactArgs(1),
+	Args: exactArgs(1),
 	RunE: runComplete,
 }
 
@@ -35,6 +35,20 @@ func runComplete(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("completing reminder: %w", err)
 	}
 
+	// Fetch the updated reminder when the backend can still find it.
+	r, getErr := s.Get(context.Background(), id)
+
+	if structuredOutput() {
+		if getErr == nil {
+			return render(r)
+		}
+		return render(completeOutput{ID: id, Completed: true})
+	}
+
+	if getErr == nil && !r.Completed && r.Recurrence != nil && r.Due != nil {
+		fmt.Printf("Completed: %s (next: %s)\n", id, r.Due.Format("Mon Jan 2, 2006 3:04 PM"))
+		return nil
+	}
 	fmt.Printf("Completed: %s\n", id)
 	return nil
 }
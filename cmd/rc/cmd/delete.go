@@ -15,7 +15,7 @@ var deleteCmd = &cobra.Command{
 Examples:
   rc delete 1768773271812-7727a989
   rc delete 1768773271812-7727a989 --backend apple`,
-	Args: cobra.ExactArgs(1),
+	Args: exactArgs(1),
 	RunE: runDelete,
 }
 
@@ -35,6 +35,10 @@ func runDelete(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("deleting reminder: %w", err)
 	}
 
+	if structuredOutput() {
+		return render(deleteOutput{ID: id, Deleted: true})
+	}
+
 	fmt.Printf("Deleted: %s\n", id)
 	return nil
 }
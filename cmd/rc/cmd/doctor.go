@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/shaneoxm/recall/internal/adapters/jsonl"
+	"github.com/shaneoxm/recall/internal/gitsync"
+	"github.com/spf13/cobra"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the local reminders file for problems",
+	Long: `Check ~/.recall/reminders.jsonl for problems:
+
+  malformed         lines that cannot be read, such as merge conflict markers
+  duplicate_id      two different reminders sharing an ID
+  invalid_priority  priorities outside 0-3
+  missing_parent    subtasks whose parent does not exist
+  orphaned_tmp      a reminders.jsonl.tmp left by an interrupted rewrite
+
+Unreadable lines are skipped when reminders are read. With --quarantine
+they are moved to reminders.jsonl.rejects, so they can be fixed by hand
+and added back.
+
+Examples:
+  rc doctor
+  rc doctor --quarantine
+  rc doctor -o json`,
+	Args: cobra.NoArgs,
+	RunE: runDoctor,
+}
+
+var doctorQuarantine bool
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+
+	doctorCmd.Flags().BoolVar(&doctorQuarantine, "quarantine", false, "move unreadable lines to reminders.jsonl.rejects")
+}
+
+// doctorOutput is the structured result of rc doctor.
+type doctorOutput struct {
+	*jsonl.Report `yaml:",inline"`
+	Quarantined   int    `json:"quarantined,omitempty" yaml:"quarantined,omitempty"`
+	Rejects       string `json:"rejects,omitempty" yaml:"rejects,omitempty"`
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	s, err := getStore()
+	if err != nil {
+		return fmt.Errorf("initializing store: %w", err)
+	}
+	tracked, _ := s.(*gitsync.Store)
+	if tracked != nil {
+		s = tracked.Unwrap()
+	}
+	local, ok := s.(*jsonl.Store)
+	if !ok {
+		return invalidArgument(errors.New("doctor only applies to the local backend"))
+	}
+
+	ctx := context.Background()
+	report, err := local.Check(ctx)
+	if err != nil {
+		return fmt.Errorf("checking: %w", err)
+	}
+	out := doctorOutput{Report: report}
+
+	if doctorQuarantine {
+		err := local.Locked(ctx, func(ctx context.Context) error {
+			moved, err := local.Quarantine(ctx)
+			if err != nil {
+				return fmt.Errorf("quarantining: %w", err)
+			}
+			if moved == 0 {
+				return nil
+			}
+			out.Quarantined = moved
+			out.Rejects = local.RejectsPath()
+			if tracked != nil {
+				paths := []string{filepath.Base(local.Path()), filepath.Base(out.Rejects)}
+				msg := fmt.Sprintf("doctor: quarantine %d lines", moved)
+				if _, err := tracked.Repo().Commit(ctx, msg, paths...); err != nil {
+					return fmt.Errorf("committing: %w", err)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if structuredOutput() {
+		return render(out)
+	}
+
+	if len(report.Problems) == 0 {
+		fmt.Printf("No problems found in %d lines (%d reminders).\n", report.Lines, report.Reminders)
+		return nil
+	}
+	for _, p := range report.Problems {
+		if p.Line > 0 {
+			fmt.Printf("line %d: %s: %s\n", p.Line, p.Kind, p.Message)
+		} else {
+			fmt.Printf("%s: %s\n", p.Kind, p.Message)
+		}
+	}
+	fmt.Printf("\n%d problems in %d lines (%d reminders).\n", len(report.Problems), report.Lines, report.Reminders)
+	if out.Quarantined > 0 {
+		fmt.Printf("Moved %d unreadable lines to %s\n", out.Quarantined, out.Rejects)
+	}
+	return nil
+}
@@ -0,0 +1,465 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shaneoxm/recall/internal/protocol"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+var editCmd = &cobra.Command{
+	Use:     "edit [id|prefix|title]",
+	Aliases: []string{"update"},
+	Short:   "Edit an existing reminder",
+	Long: `Edit an existing reminder in place, keeping its ID and creation date.
+
+Without flags, the reminder is opened in $EDITOR as YAML front matter
+followed by the notes as Markdown. Changed fields are applied on save.
+
+Examples:
+  rc edit 1768773271812-7727a989 --due friday
+  rc edit 1768773271812-7727a989 --note "Bring the receipt" --add-tag errands
+  rc edit 1768773271812-7727a989 --clear-due --priority none
+  rc edit 1768773271812-7727a989 --repeat "every 2 weeks"
+  rc edit 1768773271812-7727a989   # open in $EDITOR`,
+	Args: exactArgs(1),
+	RunE: runEdit,
+}
+
+var (
+	editTitle      string
+	editDue        string
+	editNote       string
+	editAddTags    []string
+	editRemoveTags []string
+	editAddLinks   []string
+	editPriority   string
+	editClearDue   bool
+	editRepeat     string
+	editNoRepeat   bool
+	editEditor     bool
+)
+
+func init() {
+	rootCmd.AddCommand(editCmd)
+
+	addEditFlags(editCmd.Flags())
+	editCmd.MarkFlagsMutuallyExclusive("due", "clear-due")
+	editCmd.MarkFlagsMutuallyExclusive("repeat", "no-repeat")
+}
+
+// addEditFlags defines the edit flags on fs, resetting them to their
+// defaults.
+func addEditFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&editTitle, "title", "", "new title")
+	fs.StringVarP(&editDue, "due", "d", "", "new due date (e.g., tomorrow 3pm, next friday, in 2 hours)")
+	fs.StringVarP(&editNote, "note", "n", "", "replace the note")
+	fs.StringSliceVar(&editAddTags, "add-tag", nil, "add tags (can be specified multiple times)")
+	fs.StringSliceVar(&editRemoveTags, "remove-tag", nil, "remove tags (can be specified multiple times)")
+	fs.StringSliceVar(&editAddLinks, "add-link", nil, "add links (can be specified multiple times)")
+	fs.StringVarP(&editPriority, "priority", "p", "", "priority (none, low, medium, high)")
+	fs.BoolVar(&editClearDue, "clear-due", false, "remove the due date")
+	fs.StringVarP(&editRepeat, "repeat", "r", "", "repeat rule (daily, weekly, weekdays, \"every 2 weeks\", RRULE)")
+	fs.BoolVar(&editNoRepeat, "no-repeat", false, "stop the reminder from repeating")
+	fs.BoolVarP(&editEditor, "editor", "e", false, "open the reminder in $EDITOR")
+}
+
+func runEdit(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	s, err := getStore()
+	if err != nil {
+		return fmt.Errorf("initializing store: %w", err)
+	}
+
+	reminder, err := resolveReminder(ctx, s, args[0])
+	if err != nil {
+		return err
+	}
+
+	var changed []string
+	if editEditor || !hasEditFlags(cmd) {
+		changed, err = editInEditor(reminder)
+	} else {
+		changed, err = applyEditFlags(cmd, reminder)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(changed) == 0 {
+		if structuredOutput() {
+			return render(reminder)
+		}
+		fmt.Println("No changes.")
+		return nil
+	}
+
+	reminder.UpdatedAt = time.Now()
+	if err := s.Update(ctx, reminder); err != nil {
+		return fmt.Errorf("updating reminder: %w", err)
+	}
+
+	if structuredOutput() {
+		return render(reminder)
+	}
+
+	fmt.Printf("Updated reminder: %s (ID: %s)\n", reminder.Title, reminder.ID)
+	fmt.Printf("  Changed: %s\n", strings.Join(changed, ", "))
+	return nil
+}
+
+// hasEditFlags reports whether any field-editing flag was given. Global
+// flags such as --backend don't count, so they still open the editor.
+func hasEditFlags(cmd *cobra.Command) bool {
+	found := false
+	cmd.LocalNonPersistentFlags().VisitAll(func(f *pflag.Flag) {
+		if f.Changed && f.Name != "editor" {
+			found = true
+		}
+	})
+	return found
+}
+
+func applyEditFlags(cmd *cobra.Command, r *protocol.Reminder) ([]string, error) {
+	var changed []string
+	flags := cmd.Flags()
+
+	if flags.Changed("title") {
+		if strings.TrimSpace(editTitle) == "" {
+			return nil, invalidArgument(fmt.Errorf("title cannot be empty"))
+		}
+		if editTitle != r.Title {
+			r.Title = editTitle
+			changed = append(changed, "title")
+		}
+	}
+
+	if flags.Changed("note") && editNote != r.Notes {
+		r.Notes = editNote
+		changed = append(changed, "notes")
+	}
+
+	if flags.Changed("due") {
+		due, err := parseDue(editDue)
+		if err != nil {
+			return nil, invalidArgument(fmt.Errorf("invalid due date: %w", err))
+		}
+		r.Due = &due
+		changed = append(changed, "due")
+	}
+
+	if editClearDue && r.Due != nil {
+		r.Due = nil
+		changed = append(changed, "due")
+	}
+
+	if flags.Changed("priority") {
+		priority, err := parsePriority(editPriority)
+		if err != nil {
+			return nil, invalidArgument(err)
+		}
+		if priority != r.Priority {
+			r.Priority = priority
+			changed = append(changed, "priority")
+		}
+	}
+
+	if flags.Changed("repeat") {
+		rec, err := protocol.ParseRecurrence(editRepeat)
+		if err != nil {
+			return nil, invalidArgument(fmt.Errorf("invalid repeat: %w", err))
+		}
+		r.Recurrence = rec
+		changed = append(changed, "repeat")
+	}
+
+	if editNoRepeat && r.Recurrence != nil {
+		r.Recurrence = nil
+		changed = append(changed, "repeat")
+	}
+
+	tagsChanged := false
+	for _, tag := range editAddTags {
+		if !containsFold(r.Tags, tag) {
+			r.Tags = append(r.Tags, tag)
+			tagsChanged = true
+		}
+	}
+	for _, tag := range editRemoveTags {
+		before := len(r.Tags)
+		r.Tags = slices.DeleteFunc(r.Tags, func(t string) bool { return strings.EqualFold(t, tag) })
+		tagsChanged = tagsChanged || len(r.Tags) != before
+	}
+	if tagsChanged {
+		changed = append(changed, "tags")
+	}
+
+	linksChanged := false
+	for _, link := range editAddLinks {
+		if !slices.Contains(r.Links, link) {
+			r.Links = append(r.Links, link)
+			linksChanged = true
+		}
+	}
+	if linksChanged {
+		changed = append(changed, "links")
+	}
+
+	return changed, nil
+}
+
+func containsFold(values []string, s string) bool {
+	return slices.ContainsFunc(values, func(v string) bool { return strings.EqualFold(v, s) })
+}
+
+// editDocument is the front matter presented in $EDITOR. Notes follow the
+// closing delimiter as a Markdown body.
+type editDocument struct {
+	Title    string   `yaml:"title"`
+	Due      string   `yaml:"due"`
+	Repeat   string   `yaml:"repeat"`
+	Priority string   `yaml:"priority"`
+	Tags     []string `yaml:"tags"`
+	Links    []string `yaml:"links"`
+}
+
+const editDueLayout = "2006-01-02 15:04"
+
+const editHeader = `# Edit the reminder, then save and close the editor.
+# Notes go below the closing "---" as Markdown.
+# Leave due or repeat empty to clear them. Save an empty file to abort.
+`
+
+func editInEditor(r *protocol.Reminder) ([]string, error) {
+	original, err := marshalEditDocument(r)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.CreateTemp("", "rc-edit-*.md")
+	if err != nil {
+		return nil, fmt.Errorf("creating temp file: %w", err)
+	}
+	path := f.Name()
+	defer os.Remove(path)
+
+	if _, err := f.Write(original); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("writing temp file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return nil, fmt.Errorf("closing temp file: %w", err)
+	}
+
+	if err := runEditor(path); err != nil {
+		return nil, err
+	}
+
+	edited, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading temp file: %w", err)
+	}
+	if len(bytes.TrimSpace(edited)) == 0 {
+		return nil, fmt.Errorf("edit aborted: empty file")
+	}
+	if bytes.Equal(edited, original) {
+		return nil, nil
+	}
+
+	doc, notes, err := parseEditDocument(edited)
+	if err != nil {
+		return nil, invalidArgument(err)
+	}
+	return applyEditDocument(r, doc, notes)
+}
+
+func marshalEditDocument(r *protocol.Reminder) ([]byte, error) {
+	doc := editDocument{
+		Title:    r.Title,
+		Priority: priorityName(r.Priority),
+		Tags:     r.Tags,
+		Links:    r.Links,
+	}
+	if r.Due != nil {
+		doc.Due = r.Due.Local().Format(editDueLayout)
+	}
+	if r.Recurrence != nil {
+		doc.Repeat = r.Recurrence.String()
+	}
+
+	front, err := yaml.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("encoding reminder: %w", err)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(editHeader)
+	buf.WriteString("---\n")
+	buf.Write(front)
+	buf.WriteString("---\n")
+	if r.Notes != "" {
+		buf.WriteString(r.Notes)
+		buf.WriteString("\n")
+	}
+	return buf.Bytes(), nil
+}
+
+func parseEditDocument(data []byte) (*editDocument, string, error) {
+	var lines []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.HasPrefix(line, "#") && len(lines) == 0 {
+			continue // header comments before the front matter
+		}
+		lines = append(lines, line)
+	}
+
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return nil, "", fmt.Errorf("missing front matter: expected the file to start with ---")
+	}
+
+	end := -1
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "---" {
+			end = i
+			break
+		}
+	}
+	if end < 0 {
+		return nil, "", fmt.Errorf("unterminated front matter: missing closing ---")
+	}
+
+	var doc editDocument
+	if err := yaml.Unmarshal([]byte(strings.Join(lines[1:end], "\n")), &doc); err != nil {
+		return nil, "", fmt.Errorf("parsing front matter: %w", err)
+	}
+
+	notes := strings.TrimSpace(strings.Join(lines[end+1:], "\n"))
+	return &doc, notes, nil
+}
+
+func applyEditDocument(r *protocol.Reminder, doc *editDocument, notes string) ([]string, error) {
+	var changed []string
+
+	title := strings.TrimSpace(doc.Title)
+	if title == "" {
+		return nil, invalidArgument(fmt.Errorf("title cannot be empty"))
+	}
+	if title != r.Title {
+		r.Title = title
+		changed = append(changed, "title")
+	}
+
+	if notes != r.Notes {
+		r.Notes = notes
+		changed = append(changed, "notes")
+	}
+
+	due, err := parseEditDue(strings.TrimSpace(doc.Due))
+	if err != nil {
+		return nil, invalidArgument(fmt.Errorf("invalid due date: %w", err))
+	}
+	if !sameTime(due, r.Due) {
+		r.Due = due
+		changed = append(changed, "due")
+	}
+
+	var rec *protocol.Recurrence
+	if repeat := strings.TrimSpace(doc.Repeat); repeat != "" {
+		rec, err = protocol.ParseRecurrence(repeat)
+		if err != nil {
+			return nil, invalidArgument(fmt.Errorf("invalid repeat: %w", err))
+		}
+	}
+	if recurrenceString(rec) != recurrenceString(r.Recurrence) {
+		r.Recurrence = rec
+		changed = append(changed, "repeat")
+	}
+
+	priority, err := parsePriority(doc.Priority)
+	if doc.Priority == "" {
+		priority, err = 0, nil
+	}
+	if err != nil {
+		return nil, invalidArgument(err)
+	}
+	if priority != r.Priority {
+		r.Priority = priority
+		changed = append(changed, "priority")
+	}
+
+	if !slices.Equal(doc.Tags, r.Tags) {
+		r.Tags = doc.Tags
+		changed = append(changed, "tags")
+	}
+	if !slices.Equal(doc.Links, r.Links) {
+		r.Links = doc.Links
+		changed = append(changed, "links")
+	}
+
+	return changed, nil
+}
+
+// parseEditDue accepts the layout written by marshalEditDocument as well as
+// anything parseDue understands. An empty value clears the due date.
+func parseEditDue(s string) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+	if t, err := time.ParseInLocation(editDueLayout, s, time.Local); err == nil {
+		return &t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return &t, nil
+	}
+	t, err := parseDue(s)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func recurrenceString(rec *protocol.Recurrence) string {
+	if rec == nil {
+		return ""
+	}
+	return rec.String()
+}
+
+// sameTime compares due dates at the minute precision shown in the editor.
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Truncate(time.Minute).Equal(b.Truncate(time.Minute))
+}
+
+func runEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	parts := strings.Fields(editor)
+	cmd := exec.Command(parts[0], append(parts[1:], path)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("running editor %q: %w", editor, err)
+	}
+	return nil
+}
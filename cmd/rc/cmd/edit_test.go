@@ -0,0 +1,232 @@
+package cmd
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/shaneoxm/recall/internal/protocol"
+	"github.com/spf13/cobra"
+)
+
+// testReminder returns a reminder with every editable field set.
+func testReminder() *protocol.Reminder {
+	r := protocol.NewReminder("Call mom")
+	r.SetNotes("Birthday next week")
+	r.SetDue(time.Date(2026, 3, 15, 10, 0, 0, 0, time.Local))
+	r.SetPriority(3)
+	r.Tags = []string{"family", "Phone"}
+	r.Links = []string{"https://example.com"}
+	r.Recurrence = &protocol.Recurrence{Freq: protocol.Weekly}
+	return r
+}
+
+// editFlags parses args as rc edit flags on a fresh command.
+func editFlags(t *testing.T, args ...string) *cobra.Command {
+	t.Helper()
+	cmd := &cobra.Command{Use: "edit"}
+	addEditFlags(cmd.Flags())
+	if err := cmd.ParseFlags(args); err != nil {
+		t.Fatalf("parsing %v: %v", args, err)
+	}
+	return cmd
+}
+
+func TestApplyEditFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		changed []string
+		check   func(r *protocol.Reminder) bool
+	}{
+		{
+			name:    "title",
+			args:    []string{"--title", "Call dad"},
+			changed: []string{"title"},
+			check:   func(r *protocol.Reminder) bool { return r.Title == "Call dad" },
+		},
+		{
+			name:  "same title",
+			args:  []string{"--title", "Call mom"},
+			check: func(r *protocol.Reminder) bool { return r.Title == "Call mom" },
+		},
+		{
+			name:    "clear note",
+			args:    []string{"--note", ""},
+			changed: []string{"notes"},
+			check:   func(r *protocol.Reminder) bool { return r.Notes == "" },
+		},
+		{
+			name:    "priority none",
+			args:    []string{"--priority", "none"},
+			changed: []string{"priority"},
+			check:   func(r *protocol.Reminder) bool { return r.Priority == 0 },
+		},
+		{
+			name:    "clear due",
+			args:    []string{"--clear-due"},
+			changed: []string{"due"},
+			check:   func(r *protocol.Reminder) bool { return r.Due == nil },
+		},
+		{
+			name:    "due",
+			args:    []string{"--due", "2026-04-01"},
+			changed: []string{"due"},
+			check: func(r *protocol.Reminder) bool {
+				return r.Due != nil && r.Due.Format("2006-01-02") == "2026-04-01"
+			},
+		},
+		{
+			name:    "no repeat",
+			args:    []string{"--no-repeat"},
+			changed: []string{"repeat"},
+			check:   func(r *protocol.Reminder) bool { return r.Recurrence == nil },
+		},
+		{
+			name:    "repeat",
+			args:    []string{"--repeat", "daily"},
+			changed: []string{"repeat"},
+			check:   func(r *protocol.Reminder) bool { return r.Recurrence.Freq == protocol.Daily },
+		},
+		{
+			name:    "tags are matched case-insensitively",
+			args:    []string{"--add-tag", "FAMILY", "--add-tag", "urgent", "--remove-tag", "phone"},
+			changed: []string{"tags"},
+			check:   func(r *protocol.Reminder) bool { return slices.Equal(r.Tags, []string{"family", "urgent"}) },
+		},
+		{
+			name:  "existing link",
+			args:  []string{"--add-link", "https://example.com"},
+			check: func(r *protocol.Reminder) bool { return len(r.Links) == 1 },
+		},
+		{
+			name:    "several fields",
+			args:    []string{"--title", "Call dad", "--priority", "low", "--add-link", "https://example.org"},
+			changed: []string{"title", "priority", "links"},
+			check:   func(r *protocol.Reminder) bool { return r.Priority == 1 && len(r.Links) == 2 },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := testReminder()
+			changed, err := applyEditFlags(editFlags(t, tt.args...), r)
+			if err != nil {
+				t.Fatalf("applyEditFlags failed: %v", err)
+			}
+			if !slices.Equal(changed, tt.changed) {
+				t.Errorf("expected changed %v, got %v", tt.changed, changed)
+			}
+			if !tt.check(r) {
+				t.Errorf("unexpected reminder after %v: %+v", tt.args, r)
+			}
+		})
+	}
+}
+
+func TestApplyEditFlags_Invalid(t *testing.T) {
+	for _, args := range [][]string{
+		{"--title", " "},
+		{"--priority", "urgent"},
+		{"--due", "someday maybe"},
+		{"--repeat", "sometimes"},
+	} {
+		_, err := applyEditFlags(editFlags(t, args...), testReminder())
+		var argErr *argError
+		if !errors.As(err, &argErr) {
+			t.Errorf("%v: expected an invalid argument error, got %v", args, err)
+		}
+	}
+}
+
+func TestParseEditDocument(t *testing.T) {
+	r := testReminder()
+	data, err := marshalEditDocument(r)
+	if err != nil {
+		t.Fatalf("marshalEditDocument failed: %v", err)
+	}
+
+	doc, notes, err := parseEditDocument(data)
+	if err != nil {
+		t.Fatalf("parseEditDocument failed: %v", err)
+	}
+	if doc.Title != "Call mom" || doc.Due != "2026-03-15 10:00" || doc.Priority != "high" || doc.Repeat != "FREQ=WEEKLY" {
+		t.Errorf("unexpected front matter: %+v", doc)
+	}
+	if !slices.Equal(doc.Tags, r.Tags) || !slices.Equal(doc.Links, r.Links) {
+		t.Errorf("unexpected tags or links: %+v", doc)
+	}
+	if notes != "Birthday next week" {
+		t.Errorf("unexpected notes: %q", notes)
+	}
+
+	// An unchanged document changes nothing.
+	if changed, err := applyEditDocument(r, doc, notes); err != nil || len(changed) != 0 {
+		t.Errorf("expected no changes, got %v, %v", changed, err)
+	}
+}
+
+func TestParseEditDocument_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"missing front matter": "title: Call mom\n",
+		"unterminated":         "---\ntitle: Call mom\n",
+		"bad yaml":             "---\ntitle: [Call\n---\n",
+	}
+	for name, data := range tests {
+		if _, _, err := parseEditDocument([]byte(data)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestApplyEditDocument(t *testing.T) {
+	r := testReminder()
+	data := `# header comment
+---
+title: Call mom and dad
+due: ""
+repeat: daily
+priority: ""
+tags: [family]
+links: [https://example.com]
+---
+
+Bring cake.
+`
+	doc, notes, err := parseEditDocument([]byte(data))
+	if err != nil {
+		t.Fatalf("parseEditDocument failed: %v", err)
+	}
+
+	changed, err := applyEditDocument(r, doc, notes)
+	if err != nil {
+		t.Fatalf("applyEditDocument failed: %v", err)
+	}
+	want := []string{"title", "notes", "due", "repeat", "priority", "tags"}
+	if !slices.Equal(changed, want) {
+		t.Errorf("expected changed %v, got %v", want, changed)
+	}
+	if r.Title != "Call mom and dad" || r.Notes != "Bring cake." || r.Due != nil || r.Priority != 0 {
+		t.Errorf("unexpected reminder: %+v", r)
+	}
+	if r.Recurrence.Freq != protocol.Daily || !slices.Equal(r.Tags, []string{"family"}) {
+		t.Errorf("unexpected repeat or tags: %v %v", r.Recurrence, r.Tags)
+	}
+}
+
+func TestApplyEditDocument_Invalid(t *testing.T) {
+	tests := map[string]*editDocument{
+		"empty title":  {Title: " "},
+		"bad due":      {Title: "Call", Due: "someday maybe"},
+		"bad repeat":   {Title: "Call", Repeat: "sometimes"},
+		"bad priority": {Title: "Call", Priority: "urgent"},
+	}
+	for name, doc := range tests {
+		_, err := applyEditDocument(testReminder(), doc, "")
+		var argErr *argError
+		if !errors.As(err, &argErr) {
+			t.Errorf("%s: expected an invalid argument error, got %v", name, err)
+		}
+	}
+}
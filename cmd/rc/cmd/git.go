@@ -0,0 +1,230 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/shaneoxm/recall/internal/adapters/jsonl"
+	"github.com/shaneoxm/recall/internal/config"
+	"github.com/shaneoxm/recall/internal/gitsync"
+	"github.com/spf13/cobra"
+)
+
+var gitCmd = &cobra.Command{
+	Use:   "git",
+	Short: "Share the local reminders file through git",
+	Long: `Keep ~/.recall in a git repository to share local reminders between
+machines.
+
+Once ~/.recall is a repository, every change made with rc is committed with
+a message such as "complete: Call mom". rc git sync pulls and pushes those
+commits, merging reminders edited on two machines by ID.
+
+Examples:
+  rc git init git@github.com:me/reminders.git
+  rc git sync
+  rc git status`,
+}
+
+var gitInitCmd = &cobra.Command{
+	Use:   "init [remote]",
+	Short: "Make ~/.recall a git repository",
+	Long: `Make ~/.recall a git repository, register the reminders merge driver and
+commit the reminders file. Lock files, .env and sync state are ignored.
+
+The optional remote is any git URL or path, including a local bare
+repository; it is added as origin. Running init again updates the remote
+and the merge driver.
+
+Examples:
+  rc git init
+  rc git init ~/Dropbox/reminders.git
+  rc git init git@github.com:me/reminders.git`,
+	Args: rangeArgs(0, 1),
+	RunE: runGitInit,
+}
+
+var gitSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Pull and push reminders",
+	Long: `Commit any outstanding changes, pull with rebase from origin and push.
+
+Reminders changed on both sides are merged by the merge driver. If the
+rebase still conflicts it is aborted and nothing changes.
+
+Examples:
+  rc git sync`,
+	Args: cobra.NoArgs,
+	RunE: runGitSync,
+}
+
+var gitStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show whether reminders are in sync",
+	Long: `Show the remote, commits not yet pushed or pulled, and uncommitted changes.
+
+Commits to pull are counted as of the last sync unless --fetch is given.
+
+Examples:
+  rc git status
+  rc git status --fetch`,
+	Args: cobra.NoArgs,
+	RunE: runGitStatus,
+}
+
+var gitSetupCmd = &cobra.Command{
+	Use:   "setup",
+	Short: "Register the reminders merge driver with git",
+	Long: `Register rc as the git merge driver for reminders.jsonl, so reminders
+edited on two machines merge without conflicts.
+
+This adds "reminders.jsonl merge=recall" to ~/.recall/.gitattributes and
+sets merge.recall.driver in the repository's git config to this rc
+binary. ~/.recall must already be a git repository; rc git init runs this
+for you.
+
+Examples:
+  rc git setup`,
+	Args: cobra.NoArgs,
+	RunE: runGitSetup,
+}
+
+var gitStatusFetch bool
+
+func init() {
+	rootCmd.AddCommand(gitCmd)
+	gitCmd.AddCommand(gitInitCmd)
+	gitCmd.AddCommand(gitSyncCmd)
+	gitCmd.AddCommand(gitStatusCmd)
+	gitCmd.AddCommand(gitSetupCmd)
+
+	gitStatusCmd.Flags().BoolVar(&gitStatusFetch, "fetch", false, "fetch from origin first")
+}
+
+// gitSetupOutput is the structured result of rc git setup and rc git init.
+type gitSetupOutput struct {
+	Dir    string `json:"dir" yaml:"dir"`
+	Driver string `json:"driver" yaml:"driver"`
+	Remote string `json:"remote,omitempty" yaml:"remote,omitempty"`
+}
+
+func runGitInit(cmd *cobra.Command, args []string) error {
+	cfg := config.Default()
+	driver, err := driverCommand()
+	if err != nil {
+		return err
+	}
+
+	var remote string
+	if len(args) == 1 {
+		remote = args[0]
+	}
+
+	repo := gitsync.New(cfg.DataDir)
+	if err := repo.Init(context.Background(), cfg.DataFile, driver, remote); err != nil {
+		return fmt.Errorf("initializing git: %w", err)
+	}
+
+	if structuredOutput() {
+		return render(gitSetupOutput{Dir: cfg.DataDir, Driver: driver, Remote: remote})
+	}
+	fmt.Printf("Initialized git in %s\n", cfg.DataDir)
+	if remote != "" {
+		fmt.Printf("Run rc git sync to share reminders through %s\n", remote)
+	}
+	return nil
+}
+
+func runGitSync(cmd *cobra.Command, args []string) error {
+	cfg := config.Default()
+	local, err := jsonl.New(cfg.DataPath(), jsonl.WithWarnings(warn))
+	if err != nil {
+		return fmt.Errorf("initializing store: %w", err)
+	}
+	store := gitsync.NewStore(local, gitsync.New(cfg.DataDir), cfg.DataFile)
+	result, err := store.Sync(context.Background())
+	if err != nil {
+		if errors.Is(err, gitsync.ErrNotRepository) || errors.Is(err, gitsync.ErrNoRemote) {
+			return invalidArgument(err)
+		}
+		return fmt.Errorf("syncing: %w", err)
+	}
+
+	if structuredOutput() {
+		return render(result)
+	}
+	if result.Pulled == 0 && result.Pushed == 0 {
+		fmt.Println("Already in sync.")
+		return nil
+	}
+	fmt.Printf("Pulled %d and pushed %d commits.\n", result.Pulled, result.Pushed)
+	return nil
+}
+
+func runGitStatus(cmd *cobra.Command, args []string) error {
+	repo := gitsync.New(config.Default().DataDir)
+	status, err := repo.Status(context.Background(), gitStatusFetch)
+	if err != nil {
+		if errors.Is(err, gitsync.ErrNotRepository) {
+			return invalidArgument(err)
+		}
+		return fmt.Errorf("reading git status: %w", err)
+	}
+
+	if structuredOutput() {
+		return render(status)
+	}
+
+	remote := status.Remote
+	if remote == "" {
+		remote = "none (add one with rc git init <remote>)"
+	}
+	fmt.Printf("Branch: %s\n", status.Branch)
+	fmt.Printf("Remote: %s\n", remote)
+	fmt.Printf("Ahead:  %d\n", status.Ahead)
+	fmt.Printf("Behind: %d\n", status.Behind)
+	if status.LastCommit != "" {
+		fmt.Printf("Last:   %s\n", status.LastCommit)
+	}
+	for _, path := range status.Changed {
+		fmt.Printf("Changed: %s\n", path)
+	}
+	return nil
+}
+
+func runGitSetup(cmd *cobra.Command, args []string) error {
+	cfg := config.Default()
+	driver, err := driverCommand()
+	if err != nil {
+		return err
+	}
+
+	repo := gitsync.New(cfg.DataDir)
+	if err := repo.Setup(context.Background(), cfg.DataFile, driver); err != nil {
+		if errors.Is(err, gitsync.ErrNotRepository) {
+			return invalidArgument(err)
+		}
+		return fmt.Errorf("setting up git: %w", err)
+	}
+
+	if structuredOutput() {
+		return render(gitSetupOutput{Dir: cfg.DataDir, Driver: driver})
+	}
+	fmt.Printf("Registered merge driver for %s\n", cfg.DataPath())
+	return nil
+}
+
+// driverCommand returns the merge driver command line for this rc binary.
+func driverCommand() (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("finding rc: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	return gitsync.DriverCommand(exe), nil
+}
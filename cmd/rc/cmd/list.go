@@ -19,7 +19,8 @@ Examples:
   rc list                   # List all pending reminders
   rc list --today           # List reminders due today
   rc list --tag work        # List reminders tagged "work"
-  rc list --all             # Include completed reminders`,
+  rc list --all             # Include completed reminders
+  rc list --stream          # Print as results arrive, unsorted`,
 	RunE: runList,
 }
 
@@ -31,6 +32,7 @@ var (
 	listAll       bool
 	listCompleted bool
 	listShowIDs   bool
+	listStream    bool
 )
 
 func init() {
@@ -43,6 +45,7 @@ func init() {
 	listCmd.Flags().BoolVarP(&listAll, "all", "a", false, "include completed reminders")
 	listCmd.Flags().BoolVar(&listCompleted, "completed", false, "show only completed reminders")
 	listCmd.Flags().BoolVar(&listShowIDs, "ids", false, "show reminder IDs (for complete/delete)")
+	listCmd.Flags().BoolVar(&listStream, "stream", false, "print reminders as they arrive instead of sorted (text and jsonl output)")
 }
 
 func runList(cmd *cobra.Command, args []string) error {
@@ -74,6 +77,10 @@ func runList(cmd *cobra.Command, args []string) error {
 		filter.DueBefore = &endOfWeek
 	}
 
+	if listStream {
+		return streamList(s, filter)
+	}
+
 	reminders, err := s.List(context.Background(), filter)
 	if err != nil {
 		return fmt.Errorf("listing reminders: %w", err)
@@ -90,7 +97,7 @@ func runList(cmd *cobra.Command, args []string) error {
 		reminders = completed
 	}
 
-	if len(reminders) == 0 {
+	if len(reminders) == 0 && !structuredOutput() {
 		fmt.Println("No reminders found.")
 		return nil
 	}
@@ -109,6 +116,13 @@ func runList(cmd *cobra.Command, args []string) error {
 		return reminders[i].Due.Before(*reminders[j].Due)
 	})
 
+	if structuredOutput() {
+		if reminders == nil {
+			reminders = []*protocol.Reminder{}
+		}
+		return render(reminders)
+	}
+
 	// Group subtasks by parent
 	parents := make(map[string][]*protocol.Reminder)
 	subtasks := make(map[string][]*protocol.Reminder)
@@ -137,6 +151,51 @@ func runList(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// streamList prints reminders as the store delivers them. Nothing is
+// sorted or grouped, so the first page of a remote backend shows up
+// without waiting for the rest.
+func streamList(s protocol.Store, filter *protocol.ListFilter) error {
+	if structuredOutput() && outputFormat != outputJSONL {
+		return invalidArgument(fmt.Errorf("--stream supports text and jsonl output, not %s", outputFormat))
+	}
+
+	count := 0
+	err := protocol.Each(context.Background(), s, filter, func(r *protocol.Reminder) error {
+		if listCompleted && !r.Completed {
+			return nil
+		}
+		count++
+		if structuredOutput() {
+			return render(r)
+		}
+		if r.IsSubtask {
+			printSubtask(r, listShowIDs)
+		} else {
+			printReminder(r, listShowIDs)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("listing reminders: %w", err)
+	}
+
+	if count == 0 && !structuredOutput() {
+		fmt.Println("No reminders found.")
+	}
+	return nil
+}
+
+// priorityMarker returns the marker for a priority from 1 (low) to 3
+// (high). Other values, which some backends and hand-edited files can
+// hold, are shown as "p?".
+func priorityMarker(p int) string {
+	priorities := []string{"", "!", "!!", "!!!"}
+	if p < 0 || p >= len(priorities) {
+		return "p?"
+	}
+	return priorities[p]
+}
+
 func printReminder(r *protocol.Reminder, showID bool) {
 	status := "[ ]"
 	if r.Completed {
@@ -149,15 +208,17 @@ func printReminder(r *protocol.Reminder, showID bool) {
 	}
 
 	priorityStr := ""
-	if r.Priority > 0 {
-		priorities := []string{"", "!", "!!", "!!!"}
-		priorityStr = " " + priorities[r.Priority]
+	if r.Priority != 0 {
+		priorityStr = " " + priorityMarker(r.Priority)
 	}
 
-	fmt.Printf("%s %s%s%s\n", status, r.Title, dueStr, priorityStr)
+	fmt.Printf("%s %s%s%s%s\n", status, r.Title, dueStr, priorityStr, snoozeLabel(r))
 	if showID {
 		fmt.Printf("    ID: %s\n", r.ID)
 	}
+	if r.Recurrence != nil {
+		fmt.Printf("    Repeats: %s\n", r.Recurrence.Describe())
+	}
 
 	if r.Notes != "" {
 		fmt.Printf("    Note: %s\n", r.Notes)
@@ -183,7 +244,7 @@ func printSubtask(r *protocol.Reminder, showID bool) {
 		dueStr = fmt.Sprintf(" (due: %s)", r.Due.Format("Mon Jan 2"))
 	}
 
-	fmt.Printf("  └─ %s %s%s\n", status, r.Title, dueStr)
+	fmt.Printf("  └─ %s %s%s%s\n", status, r.Title, dueStr, snoozeLabel(r))
 	if showID {
 		fmt.Printf("      ID: %s\n", r.ID)
 	}
@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/shaneoxm/recall/internal/protocol"
+)
+
+func TestPrintReminderPriority(t *testing.T) {
+	tests := []struct {
+		priority int
+		want     string
+	}{
+		{0, "[ ] Call mom\n"},
+		{1, "[ ] Call mom !\n"},
+		{3, "[ ] Call mom !!!\n"},
+		{4, "[ ] Call mom p?\n"},
+		{-1, "[ ] Call mom p?\n"},
+	}
+
+	for _, tt := range tests {
+		r := protocol.NewReminder("Call mom")
+		r.Priority = tt.priority
+		got := captureStdout(t, func() { printReminder(r, false) })
+		if got != tt.want {
+			t.Errorf("priority %d: got %q, want %q", tt.priority, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/shaneoxm/recall/internal/mcp"
+	"github.com/spf13/cobra"
+)
+
+var mcpCmd = &cobra.Command{
+	Use:   "mcp",
+	Short: "Run as an MCP server over stdio",
+	Long: `Run Recall as a Model Context Protocol server.
+
+The server speaks JSON-RPC 2.0 over stdin/stdout and exposes the
+add_reminder, list_reminders, get_reminder, update_reminder,
+complete_reminder, snooze_reminder and delete_reminder tools backed by the
+selected backend.
+
+Examples:
+  rc mcp
+  rc mcp --backend todoist`,
+	Args: cobra.NoArgs,
+	RunE: runMCP,
+}
+
+func init() {
+	rootCmd.AddCommand(mcpCmd)
+}
+
+func runMCP(cmd *cobra.Command, args []string) error {
+	s, err := getStore()
+	if err != nil {
+		return fmt.Errorf("initializing store: %w", err)
+	}
+
+	server := mcp.New(s, Version)
+	server.DueParser = parseDue
+
+	return server.Serve(context.Background(), os.Stdin, os.Stdout)
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/shaneoxm/recall/internal/adapters/jsonl"
+	"github.com/spf13/cobra"
+)
+
+var mergeDriverCmd = &cobra.Command{
+	Use:   "merge-driver <base> <ours> <theirs>",
+	Short: "Merge two versions of a reminders file (git merge driver)",
+	Long: `Merge two versions of a reminders file for git, writing the result to
+<ours>. Git runs this for reminders.jsonl once "rc git setup" has
+registered it; there is no need to run it by hand.
+
+Reminders are matched by ID. Fields changed on one side are kept, and a
+field changed on both sides takes the value from the side updated last.
+Tags and links from both sides are kept. A reminder completed on either
+side stays completed.`,
+	Hidden: true,
+	Args:   exactArgs(3),
+	RunE:   runMergeDriver,
+}
+
+func init() {
+	rootCmd.AddCommand(mergeDriverCmd)
+}
+
+func runMergeDriver(cmd *cobra.Command, args []string) error {
+	result, err := jsonl.Merge(args[0], args[1], args[2])
+	if err != nil {
+		return fmt.Errorf("merging reminders: %w", err)
+	}
+
+	if structuredOutput() {
+		return render(result)
+	}
+	return nil
+}
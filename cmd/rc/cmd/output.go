@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+
+	"github.com/shaneoxm/recall/internal/adapters/jsonl"
+	"github.com/shaneoxm/recall/internal/adapters/todoist"
+	"github.com/shaneoxm/recall/internal/protocol"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats accepted by --output.
+const (
+	outputText  = "text"
+	outputJSON  = "json"
+	outputJSONL = "jsonl"
+	outputYAML  = "yaml"
+)
+
+// Stable error codes included in structured error output.
+const (
+	codeNotFound        = "not_found"
+	codeInvalidArgument = "invalid_argument"
+	codeUnauthorized    = "unauthorized"
+	codeRateLimited     = "rate_limited"
+	codeLocked          = "locked"
+	codeError           = "error"
+)
+
+var outputFormat string
+
+// argError marks errors caused by bad user input.
+type argError struct {
+	err error
+}
+
+func (e *argError) Error() string { return e.err.Error() }
+func (e *argError) Unwrap() error { return e.err }
+
+// invalidArgument wraps err so that it is reported with the invalid_argument code.
+func invalidArgument(err error) error {
+	return &argError{err: err}
+}
+
+// exactArgs is cobra.ExactArgs with errors reported as invalid_argument.
+func exactArgs(n int) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if err := cobra.ExactArgs(n)(cmd, args); err != nil {
+			return invalidArgument(err)
+		}
+		return nil
+	}
+}
+
+// rangeArgs is cobra.RangeArgs with failures reported as invalid_argument.
+func rangeArgs(min, max int) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if err := cobra.RangeArgs(min, max)(cmd, args); err != nil {
+			return invalidArgument(err)
+		}
+		return nil
+	}
+}
+
+// errorOutput is the structured form of a command failure.
+type errorOutput struct {
+	Error errorDetail `json:"error" yaml:"error"`
+}
+
+type errorDetail struct {
+	Code    string `json:"code" yaml:"code"`
+	Message string `json:"message" yaml:"message"`
+}
+
+// completeOutput is the structured result of rc complete when the
+// completed reminder cannot be fetched back from the backend.
+type completeOutput struct {
+	ID        string `json:"id" yaml:"id"`
+	Completed bool   `json:"completed" yaml:"completed"`
+}
+
+// deleteOutput is the structured result of rc delete.
+type deleteOutput struct {
+	ID      string `json:"id" yaml:"id"`
+	Deleted bool   `json:"deleted" yaml:"deleted"`
+}
+
+func validateOutputFormat() error {
+	switch outputFormat {
+	case outputText, outputJSON, outputJSONL, outputYAML:
+		return nil
+	default:
+		return invalidArgument(fmt.Errorf("unknown output format: %s (use: text, json, jsonl, yaml)", outputFormat))
+	}
+}
+
+// structuredOutput reports whether results should be encoded instead of
+// printed as human-readable text.
+func structuredOutput() bool {
+	return outputFormat != "" && outputFormat != outputText
+}
+
+// render writes v to stdout in the selected structured format.
+func render(v any) error {
+	return encode(os.Stdout, outputFormat, v)
+}
+
+func encode(w io.Writer, format string, v any) error {
+	switch format {
+	case outputJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case outputJSONL:
+		enc := json.NewEncoder(w)
+		// Slices are written one element per line.
+		rv := reflect.ValueOf(v)
+		if rv.Kind() == reflect.Slice {
+			for i := 0; i < rv.Len(); i++ {
+				if err := enc.Encode(rv.Index(i).Interface()); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		return enc.Encode(v)
+	case outputYAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+		return enc.Close()
+	default:
+		return fmt.Errorf("unknown output format: %s", format)
+	}
+}
+
+// renderError writes err as a structured error object.
+func renderError(err error) {
+	out := errorOutput{Error: errorDetail{Code: errorCode(err), Message: err.Error()}}
+	if encErr := render(out); encErr != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	}
+}
+
+func errorCode(err error) string {
+	var argErr *argError
+	switch {
+	case errors.Is(err, protocol.ErrNotFound):
+		return codeNotFound
+	case errors.As(err, &argErr), errors.Is(err, protocol.ErrInvalid):
+		return codeInvalidArgument
+	case errors.Is(err, todoist.ErrUnauthorized):
+		return codeUnauthorized
+	case errors.Is(err, todoist.ErrRateLimited):
+		return codeRateLimited
+	case errors.Is(err, jsonl.ErrLocked):
+		return codeLocked
+	default:
+		return codeError
+	}
+}
+
+// errorHint suggests a fix for errors the user can act on.
+func errorHint(err error) string {
+	switch errorCode(err) {
+	case codeUnauthorized:
+		return "Check TODOIST_API_TOKEN in ~/.recall/.env; tokens are listed under Todoist Settings > Integrations > Developer."
+	case codeRateLimited:
+		return "Todoist is throttling requests. Wait a minute and try again."
+	case codeLocked:
+		return "Another rc process is using the reminders file. Try again in a moment."
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/shaneoxm/recall/internal/adapters/jsonl"
+	"github.com/shaneoxm/recall/internal/adapters/todoist"
+	"github.com/shaneoxm/recall/internal/protocol"
+)
+
+// captureStdout returns what fn writes to os.Stdout.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("creating pipe: %v", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	done := make(chan []byte)
+	go func() {
+		data, _ := io.ReadAll(r)
+		done <- data
+	}()
+	fn()
+	w.Close()
+	return string(<-done)
+}
+
+// setOutputFormat selects format for the rest of the test.
+func setOutputFormat(t *testing.T, format string) {
+	t.Helper()
+	old := outputFormat
+	outputFormat = format
+	t.Cleanup(func() { outputFormat = old })
+}
+
+func TestEncode(t *testing.T) {
+	deleted := []deleteOutput{{ID: "a1", Deleted: true}, {ID: "b2", Deleted: false}}
+
+	tests := []struct {
+		format string
+		v      any
+		want   string
+	}{
+		{outputJSON, deleted, `[
+  {
+    "id": "a1",
+    "deleted": true
+  },
+  {
+    "id": "b2",
+    "deleted": false
+  }
+]
+`},
+		{outputJSON, deleted[0], `{
+  "id": "a1",
+  "deleted": true
+}
+`},
+		{outputJSONL, deleted, `{"id":"a1","deleted":true}
+{"id":"b2","deleted":false}
+`},
+		{outputJSONL, deleted[0], `{"id":"a1","deleted":true}
+`},
+		{outputJSONL, []deleteOutput{}, ""},
+		{outputYAML, deleted, `- id: a1
+  deleted: true
+- id: b2
+  deleted: false
+`},
+		{outputYAML, errorOutput{Error: errorDetail{Code: codeNotFound, Message: "gone"}}, `error:
+  code: not_found
+  message: gone
+`},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := encode(&buf, tt.format, tt.v); err != nil {
+			t.Errorf("%s %T: %v", tt.format, tt.v, err)
+			continue
+		}
+		if got := buf.String(); got != tt.want {
+			t.Errorf("%s %T:\ngot:\n%s\nwant:\n%s", tt.format, tt.v, got, tt.want)
+		}
+	}
+
+	if err := encode(io.Discard, "xml", deleted); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
+
+func TestErrorCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{fmt.Errorf("reminder %w", protocol.ErrNotFound), codeNotFound},
+		{jsonl.ErrNotFound, codeNotFound},
+		{invalidArgument(errors.New("title cannot be empty")), codeInvalidArgument},
+		{fmt.Errorf("adding: %w", invalidArgument(errors.New("bad due"))), codeInvalidArgument},
+		{fmt.Errorf("%w: tag %q", protocol.ErrInvalid, "1on1"), codeInvalidArgument},
+		{fmt.Errorf("listing: %w", todoist.ErrUnauthorized), codeUnauthorized},
+		{fmt.Errorf("listing: %w", todoist.ErrRateLimited), codeRateLimited},
+		{fmt.Errorf("adding: %w", jsonl.ErrLocked), codeLocked},
+		{errors.New("connection refused"), codeError},
+	}
+
+	for _, tt := range tests {
+		if got := errorCode(tt.err); got != tt.want {
+			t.Errorf("errorCode(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestRenderError(t *testing.T) {
+	err := fmt.Errorf("reminder %w", protocol.ErrNotFound)
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{outputJSON, `{
+  "error": {
+    "code": "not_found",
+    "message": "reminder not found"
+  }
+}
+`},
+		{outputJSONL, `{"error":{"code":"not_found","message":"reminder not found"}}
+`},
+		{outputYAML, `error:
+  code: not_found
+  message: reminder not found
+`},
+	}
+
+	for _, tt := range tests {
+		setOutputFormat(t, tt.format)
+		if got := captureStdout(t, func() { renderError(err) }); got != tt.want {
+			t.Errorf("%s:\ngot:\n%s\nwant:\n%s", tt.format, got, tt.want)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 
@@ -21,11 +22,30 @@ Examples:
   rc add "Review PR" --due monday --link "https://github.com/..." --tag work
   rc list --today
   rc list --tag work
-  rc fetch "Call mom" --full`,
+  rc fetch "Call mom" --full
+  rc list --output json | jq '.[].title'`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return validateOutputFormat()
+	},
+	// Errors are printed by Execute so they can be rendered structurally.
+	SilenceErrors: true,
 }
 
 func Execute() error {
-	return rootCmd.Execute()
+	err := rootCmd.Execute()
+	if err == nil {
+		return nil
+	}
+
+	if structuredOutput() && validateOutputFormat() == nil {
+		renderError(err)
+	} else {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		if hint := errorHint(err); hint != "" {
+			fmt.Fprintln(os.Stderr, hint)
+		}
+	}
+	return err
 }
 
 func init() {
@@ -36,4 +56,21 @@ func init() {
 
 	rootCmd.PersistentFlags().StringP("config", "c", "", "config file (default $HOME/.recall/config.yaml)")
 	rootCmd.PersistentFlags().StringVarP(&backendFlag, "backend", "b", "local", "storage backend (local, apple, todoist)")
+	rootCmd.PersistentFlags().StringVar(&projectFlag, "project", "", "Todoist project to use (default $TODOIST_PROJECT, or all projects)")
+	rootCmd.PersistentFlags().StringVar(&sectionFlag, "section", "", "Todoist section within the project (default $TODOIST_SECTION)")
+	rootCmd.PersistentFlags().StringVar(&listFlag, "list", "", "Apple Reminders list to use (default $APPLE_REMINDERS_LIST, or Recall)")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", outputText, "output format (text, json, jsonl, yaml)")
+
+	// Keep stdout parseable: no usage text mixed into structured output.
+	// This runs after flags are parsed but before arguments are validated.
+	cobra.OnInitialize(silenceUsageForStructuredOutput)
+
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		silenceUsageForStructuredOutput()
+		return invalidArgument(err)
+	})
+}
+
+func silenceUsageForStructuredOutput() {
+	rootCmd.SilenceUsage = structuredOutput()
 }
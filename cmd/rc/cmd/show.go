@@ -0,0 +1,231 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/shaneoxm/recall/internal/protocol"
+	"github.com/spf13/cobra"
+)
+
+var showCmd = &cobra.Command{
+	Use:     "show [id|prefix|title]",
+	Aliases: []string{"fetch"},
+	Short:   "Show a single reminder with full context",
+	Long: `Show every field of a single reminder, including links, subtasks,
+timestamps and backend-specific metadata.
+
+The reminder can be given by ID, a unique ID prefix, or its title.
+
+Examples:
+  rc show 1768773271812-7727a989
+  rc show 1768773271812
+  rc fetch "Call mom" --full`,
+	Args: exactArgs(1),
+	RunE: runShow,
+}
+
+var showFull bool
+
+func init() {
+	rootCmd.AddCommand(showCmd)
+
+	showCmd.Flags().BoolVar(&showFull, "full", false, "also show notes, links and tags of subtasks")
+}
+
+// showOutput is the structured result of rc show.
+type showOutput struct {
+	Reminder *protocol.Reminder   `json:"reminder" yaml:"reminder"`
+	Subtasks []*protocol.Reminder `json:"subtasks" yaml:"subtasks"`
+	Backend  string               `json:"backend" yaml:"backend"`
+}
+
+func runShow(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	s, err := getStore()
+	if err != nil {
+		return fmt.Errorf("initializing store: %w", err)
+	}
+
+	reminder, err := resolveReminder(ctx, s, args[0])
+	if err != nil {
+		return err
+	}
+
+	all, err := s.List(ctx, &protocol.ListFilter{IncludeCompleted: true})
+	if err != nil {
+		return fmt.Errorf("listing subtasks: %w", err)
+	}
+
+	subtasks := []*protocol.Reminder{}
+	var parent *protocol.Reminder
+	for _, r := range all {
+		if r.ParentID == reminder.ID && r.ID != reminder.ID {
+			subtasks = append(subtasks, r)
+		}
+		if reminder.ParentID != "" && r.ID == reminder.ParentID {
+			parent = r
+		}
+	}
+	sort.SliceStable(subtasks, func(i, j int) bool {
+		return subtasks[i].CreatedAt.Before(subtasks[j].CreatedAt)
+	})
+
+	if structuredOutput() {
+		return render(showOutput{Reminder: reminder, Subtasks: subtasks, Backend: backendFlag})
+	}
+
+	printDetails(reminder, parent, subtasks)
+	return nil
+}
+
+const detailTimeLayout = "Mon Jan 2, 2006 3:04 PM"
+
+func printDetails(r *protocol.Reminder, parent *protocol.Reminder, subtasks []*protocol.Reminder) {
+	fmt.Println(r.Title)
+	field := func(name, value string) {
+		fmt.Printf("  %-10s %s\n", name+":", value)
+	}
+
+	field("ID", r.ID)
+	if r.Completed {
+		status := "completed"
+		if r.CompletedAt != nil {
+			status += " " + r.CompletedAt.Local().Format(detailTimeLayout)
+		}
+		field("Status", status)
+	} else {
+		field("Status", "pending")
+	}
+	if r.Due != nil {
+		field("Due", r.Due.Local().Format(detailTimeLayout))
+	}
+	if r.Recurrence != nil {
+		field("Repeats", fmt.Sprintf("%s (%s)", r.Recurrence.Describe(), r.Recurrence))
+	}
+	field("Priority", priorityName(r.Priority))
+	if r.SnoozeCount > 0 {
+		snoozed := fmt.Sprintf("%d times", r.SnoozeCount)
+		if r.LastSnoozedAt != nil {
+			snoozed += ", last " + r.LastSnoozedAt.Local().Format(detailTimeLayout)
+		}
+		field("Snoozed", snoozed)
+	}
+	if len(r.Tags) > 0 {
+		field("Tags", strings.Join(r.Tags, ", "))
+	}
+	if r.ParentID != "" {
+		if parent != nil {
+			field("Parent", fmt.Sprintf("%s (ID: %s)", parent.Title, parent.ID))
+		} else {
+			field("Parent", r.ParentID)
+		}
+	}
+	if !r.CreatedAt.IsZero() {
+		field("Created", r.CreatedAt.Local().Format(detailTimeLayout))
+	}
+	if !r.UpdatedAt.IsZero() {
+		field("Updated", r.UpdatedAt.Local().Format(detailTimeLayout))
+	}
+	field("Backend", backendFlag)
+
+	if r.Notes != "" {
+		fmt.Println("  Notes:")
+		for _, line := range strings.Split(r.Notes, "\n") {
+			fmt.Printf("    %s\n", line)
+		}
+	}
+
+	if len(r.Links) > 0 {
+		fmt.Println("  Links:")
+		for _, link := range r.Links {
+			fmt.Printf("    - %s\n", link)
+		}
+	}
+
+	if len(subtasks) > 0 {
+		fmt.Println("  Subtasks:")
+		for _, sub := range subtasks {
+			if showFull {
+				printSubtask(sub, true)
+				continue
+			}
+			status := "[ ]"
+			if sub.Completed {
+				status = "[x]"
+			}
+			fmt.Printf("    %s %s (ID: %s)\n", status, sub.Title, sub.ID)
+		}
+	}
+
+	if len(r.Metadata) > 0 {
+		keys := make([]string, 0, len(r.Metadata))
+		for k := range r.Metadata {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		fmt.Println("  Metadata:")
+		for _, k := range keys {
+			fmt.Printf("    %s: %s\n", k, r.Metadata[k])
+		}
+	}
+}
+
+// resolveReminder finds a reminder by exact ID, unique ID prefix, exact
+// title, or unique title substring, in that order.
+func resolveReminder(ctx context.Context, s protocol.Store, query string) (*protocol.Reminder, error) {
+	r, err := s.Get(ctx, query)
+	if err == nil {
+		return r, nil
+	}
+	if !errors.Is(err, protocol.ErrNotFound) {
+		return nil, fmt.Errorf("getting reminder: %w", err)
+	}
+
+	all, err := s.List(ctx, &protocol.ListFilter{IncludeCompleted: true})
+	if err != nil {
+		return nil, fmt.Errorf("listing reminders: %w", err)
+	}
+
+	matchers := []func(*protocol.Reminder) bool{
+		func(r *protocol.Reminder) bool { return strings.HasPrefix(r.ID, query) },
+		func(r *protocol.Reminder) bool { return strings.EqualFold(r.Title, query) },
+		func(r *protocol.Reminder) bool {
+			return strings.Contains(strings.ToLower(r.Title), strings.ToLower(query))
+		},
+	}
+
+	for _, match := range matchers {
+		var found []*protocol.Reminder
+		for _, r := range all {
+			if match(r) {
+				found = append(found, r)
+			}
+		}
+
+		switch len(found) {
+		case 0:
+			continue
+		case 1:
+			return found[0], nil
+		default:
+			return nil, ambiguousError(query, found)
+		}
+	}
+
+	return nil, fmt.Errorf("no reminder matches %q: %w", query, protocol.ErrNotFound)
+}
+
+func ambiguousError(query string, matches []*protocol.Reminder) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%q matches %d reminders, use an ID:", query, len(matches))
+	for _, r := range matches {
+		fmt.Fprintf(&b, "\n  %s  %s", r.ID, r.Title)
+	}
+	return invalidArgument(errors.New(b.String()))
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/shaneoxm/recall/internal/adapters/jsonl"
+	"github.com/shaneoxm/recall/internal/protocol"
+)
+
+func TestResolveReminder(t *testing.T) {
+	ctx := context.Background()
+	store, err := jsonl.New(filepath.Join(t.TempDir(), "reminders.jsonl"))
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	for _, r := range []struct{ id, title string }{
+		{"abc123", "Call mom"},
+		{"abd456", "Call dad"},
+		{"f00789", "Buy milk"},
+		{"abc", "Water plants"},
+	} {
+		reminder := protocol.NewReminder(r.title)
+		reminder.ID = r.id
+		if err := store.Add(ctx, reminder); err != nil {
+			t.Fatalf("failed to add: %v", err)
+		}
+	}
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"abc123", "abc123"},    // exact ID
+		{"abc", "abc"},          // an exact ID wins over longer IDs with it as prefix
+		{"f00", "f00789"},       // unique ID prefix
+		{"abd", "abd456"},       // unique ID prefix
+		{"call MOM", "abc123"},  // exact title, any case
+		{"milk", "f00789"},      // unique title substring
+		{"water plants", "abc"}, // exact title
+		{"Water", "abc"},        // unique title substring
+		{"Call dad", "abd456"},  // exact title over the shared "Call" substring
+	}
+	for _, tt := range tests {
+		got, err := resolveReminder(ctx, store, tt.query)
+		if err != nil {
+			t.Errorf("resolveReminder(%q) failed: %v", tt.query, err)
+			continue
+		}
+		if got.ID != tt.want {
+			t.Errorf("resolveReminder(%q) = %s, want %s", tt.query, got.ID, tt.want)
+		}
+	}
+
+	// Ambiguous prefixes and substrings list the candidates.
+	for _, query := range []string{"ab", "call"} {
+		_, err := resolveReminder(ctx, store, query)
+		var argErr *argError
+		if !errors.As(err, &argErr) {
+			t.Fatalf("resolveReminder(%q): expected an invalid argument error, got %v", query, err)
+		}
+		if !strings.Contains(err.Error(), "abc123") || !strings.Contains(err.Error(), "abd456") {
+			t.Errorf("resolveReminder(%q): expected both candidates in %q", query, err)
+		}
+	}
+
+	if _, err := resolveReminder(ctx, store, "dentist"); !errors.Is(err, protocol.ErrNotFound) {
+		t.Errorf("expected not found, got %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shaneoxm/recall/internal/dateparse"
+	"github.com/shaneoxm/recall/internal/protocol"
+	"github.com/spf13/cobra"
+)
+
+var snoozeCmd = &cobra.Command{
+	Use:   "snooze [id|prefix|title] [duration]",
+	Short: "Push a reminder's due date forward",
+	Long: `Snooze a reminder by a duration or until a given time.
+
+Durations are added to the later of now and the current due date, so
+snoozing never moves a reminder earlier. Each snooze is counted; reminders
+snoozed 3 or more times are flagged in rc list.
+
+Examples:
+  rc snooze 1768773271812-7727a989 2h
+  rc snooze "Call mom" 30m
+  rc snooze "Call mom" "1 day"
+  rc snooze 1768773271812 --until monday
+  rc snooze 1768773271812 --until "tomorrow 3pm"`,
+	Args: rangeArgs(1, 2),
+	RunE: runSnooze,
+}
+
+var snoozeUntil string
+
+func init() {
+	rootCmd.AddCommand(snoozeCmd)
+
+	snoozeCmd.Flags().StringVarP(&snoozeUntil, "until", "u", "", "snooze until this time (e.g., monday, tomorrow 3pm)")
+}
+
+func runSnooze(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	now := time.Now()
+	until, delay, err := snoozeTarget(args, now)
+	if err != nil {
+		return invalidArgument(err)
+	}
+
+	s, err := getStore()
+	if err != nil {
+		return fmt.Errorf("initializing store: %w", err)
+	}
+
+	reminder, err := resolveReminder(ctx, s, args[0])
+	if err != nil {
+		return err
+	}
+
+	if delay > 0 {
+		// Push from the current due date when it is still ahead of us.
+		base := now
+		if reminder.Due != nil && reminder.Due.After(now) {
+			base = *reminder.Due
+		}
+		until = base.Add(delay)
+	}
+
+	reminder.Snooze(until)
+	if err := s.Update(ctx, reminder); err != nil {
+		return fmt.Errorf("updating reminder: %w", err)
+	}
+
+	if structuredOutput() {
+		return render(reminder)
+	}
+
+	fmt.Printf("Snoozed: %s until %s\n", reminder.Title, reminder.Due.Format("Mon Jan 2, 2006 3:04 PM"))
+	if reminder.ChronicallySnoozed() {
+		fmt.Printf("  Snoozed %d times - consider rescheduling or dropping it.\n", reminder.SnoozeCount)
+	}
+	return nil
+}
+
+// snoozeTarget returns either an absolute time from --until or a delay
+// from the duration argument.
+func snoozeTarget(args []string, now time.Time) (time.Time, time.Duration, error) {
+	switch {
+	case snoozeUntil != "" && len(args) > 1:
+		return time.Time{}, 0, fmt.Errorf("give either a duration or --until, not both")
+	case snoozeUntil != "":
+		until, err := parseDue(snoozeUntil)
+		if err != nil {
+			return time.Time{}, 0, fmt.Errorf("invalid --until: %w", err)
+		}
+		if !until.After(now) {
+			return time.Time{}, 0, fmt.Errorf("--until must be in the future")
+		}
+		return until, 0, nil
+	case len(args) > 1:
+		d, err := dateparse.ParseDuration(args[1])
+		if err != nil {
+			return time.Time{}, 0, err
+		}
+		return time.Time{}, d, nil
+	default:
+		return time.Time{}, 0, fmt.Errorf("give a duration (e.g., 2h) or --until")
+	}
+}
+
+// snoozeLabel describes chronic deferral for list output.
+func snoozeLabel(r *protocol.Reminder) string {
+	if !r.ChronicallySnoozed() {
+		return ""
+	}
+	return fmt.Sprintf(" [snoozed %dx]", r.SnoozeCount)
+}
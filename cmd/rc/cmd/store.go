@@ -1,18 +1,24 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"os"
 
 	"github.com/shaneoxm/recall/internal/adapters/apple"
 	"github.com/shaneoxm/recall/internal/adapters/jsonl"
 	"github.com/shaneoxm/recall/internal/adapters/todoist"
 	"github.com/shaneoxm/recall/internal/config"
+	"github.com/shaneoxm/recall/internal/gitsync"
 	"github.com/shaneoxm/recall/internal/protocol"
 )
 
 var (
 	store       protocol.Store
 	backendFlag string
+	projectFlag string
+	sectionFlag string
+	listFlag    string
 )
 
 func getStore() (protocol.Store, error) {
@@ -20,30 +26,77 @@ func getStore() (protocol.Store, error) {
 		return store, nil
 	}
 
-	backend := backendFlag
-	if backend == "" {
-		backend = "local" // default
+	s, err := openStore(backendFlag)
+	if err != nil {
+		return nil, err
+	}
+	store = s
+	return store, nil
+}
+
+// openStore opens the named backend. Aliases are accepted; see
+// canonicalBackend.
+func openStore(name string) (protocol.Store, error) {
+	backend, err := canonicalBackend(name)
+	if err != nil {
+		return nil, err
 	}
 
-	var err error
 	switch backend {
-	case "local", "jsonl":
-		cfg := config.Default()
-		store, err = jsonl.New(cfg.DataPath())
-	case "apple", "reminders":
-		store = apple.New("Recall")
+	case "apple":
+		list := config.Default().AppleList
+		if listFlag != "" {
+			list = listFlag
+		}
+		return apple.New(list), nil
 	case "todoist":
 		cfg := config.Default()
 		if cfg.TodoistToken == "" {
 			return nil, fmt.Errorf("TODOIST_API_TOKEN environment variable not set")
 		}
-		store = todoist.New(cfg.TodoistToken, "")
+		project, section := cfg.TodoistProject, cfg.TodoistSection
+		if projectFlag != "" {
+			project = projectFlag
+		}
+		if sectionFlag != "" {
+			section = sectionFlag
+		}
+		return todoist.New(cfg.TodoistToken, project, todoist.WithSection(section)), nil
 	default:
-		return nil, fmt.Errorf("unknown backend: %s (use: local, apple, todoist)", backend)
+		cfg := config.Default()
+		local, err := jsonl.New(cfg.DataPath(), jsonl.WithWarnings(warn))
+		if err != nil {
+			return nil, err
+		}
+		// Once rc git init has made the data directory a repository,
+		// every change is committed.
+		if repo := gitsync.New(cfg.DataDir); repo.Exists() {
+			return gitsync.NewStore(local, repo, cfg.DataFile), nil
+		}
+		return local, nil
 	}
+}
 
-	if err != nil {
-		return nil, err
+// warn reports a problem that did not stop the command on stderr, so
+// structured output stays parseable.
+func warn(err error) {
+	fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	var malformed *jsonl.MalformedError
+	if errors.As(err, &malformed) {
+		fmt.Fprintln(os.Stderr, "Run rc doctor to inspect them, and rc doctor --quarantine to move them aside.")
+	}
+}
+
+// canonicalBackend maps a backend name or alias to its canonical name.
+func canonicalBackend(name string) (string, error) {
+	switch name {
+	case "", "local", "jsonl":
+		return "local", nil
+	case "apple", "reminders":
+		return "apple", nil
+	case "todoist":
+		return "todoist", nil
+	default:
+		return "", fmt.Errorf("unknown backend: %s (use: local, apple, todoist)", name)
 	}
-	return store, nil
 }
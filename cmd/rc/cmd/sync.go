@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shaneoxm/recall/internal/config"
+	"github.com/shaneoxm/recall/internal/syncer"
+	"github.com/spf13/cobra"
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Copy reminders between backends",
+	Long: `Copy new and changed reminders from one backend to another.
+
+Recall remembers which reminders correspond across the two backends in
+~/.recall/sync/, so running sync again only sends what changed. When a
+reminder changed on both sides since the last sync it is reported as a
+conflict and skipped; edit one side and sync again. Completing or
+reopening a reminder is synced like any other change. Deletions are not
+propagated: the copy left on the other side stops being synced and is
+not copied back, even with --both.
+
+With --both, changes and new reminders also flow back from --to to --from.
+
+Examples:
+  rc sync --from local --to todoist --dry-run
+  rc sync --from local --to todoist
+  rc sync --from local --to apple --both`,
+	Args: cobra.NoArgs,
+	RunE: runSync,
+}
+
+var (
+	syncFrom   string
+	syncTo     string
+	syncBoth   bool
+	syncDryRun bool
+)
+
+func init() {
+	rootCmd.AddCommand(syncCmd)
+
+	syncCmd.Flags().StringVar(&syncFrom, "from", "local", "backend to copy from")
+	syncCmd.Flags().StringVar(&syncTo, "to", "", "backend to copy to (required)")
+	syncCmd.Flags().BoolVar(&syncBoth, "both", false, "sync in both directions")
+	syncCmd.Flags().BoolVarP(&syncDryRun, "dry-run", "n", false, "print the planned changes without making them")
+	syncCmd.MarkFlagRequired("to")
+}
+
+// syncOutput is the structured result of rc sync.
+type syncOutput struct {
+	DryRun  bool             `json:"dry_run" yaml:"dry_run"`
+	Actions []*syncer.Action `json:"actions" yaml:"actions"`
+}
+
+func runSync(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	fromName, err := canonicalBackend(syncFrom)
+	if err != nil {
+		return invalidArgument(err)
+	}
+	toName, err := canonicalBackend(syncTo)
+	if err != nil {
+		return invalidArgument(err)
+	}
+	if fromName == toName {
+		return invalidArgument(fmt.Errorf("--from and --to are both %s", fromName))
+	}
+
+	from, err := openStore(fromName)
+	if err != nil {
+		return fmt.Errorf("initializing %s store: %w", fromName, err)
+	}
+	to, err := openStore(toName)
+	if err != nil {
+		return fmt.Errorf("initializing %s store: %w", toName, err)
+	}
+
+	state, err := syncer.LoadState(syncer.StatePath(config.Default().DataDir, fromName, toName))
+	if err != nil {
+		return err
+	}
+
+	sy := &syncer.Syncer{
+		From:  syncer.Endpoint{Name: fromName, Store: from},
+		To:    syncer.Endpoint{Name: toName, Store: to},
+		State: state,
+		Both:  syncBoth,
+	}
+
+	plan, err := sy.Plan(ctx)
+	if err != nil {
+		return fmt.Errorf("planning sync: %w", err)
+	}
+
+	if !syncDryRun {
+		if err := sy.Apply(ctx, plan); err != nil {
+			return fmt.Errorf("applying sync: %w", err)
+		}
+	}
+
+	if structuredOutput() {
+		if err := render(syncOutput{DryRun: syncDryRun, Actions: plan.Actions}); err != nil {
+			return err
+		}
+	} else {
+		printSyncPlan(plan, syncDryRun)
+	}
+
+	if failed := plan.Failed(); len(failed) > 0 {
+		return fmt.Errorf("%d of %d sync actions failed", len(failed), len(plan.Actions))
+	}
+	return nil
+}
+
+func printSyncPlan(plan *syncer.Plan, dryRun bool) {
+	if len(plan.Actions) == 0 {
+		fmt.Println("Already in sync.")
+		return
+	}
+
+	if dryRun {
+		fmt.Println("Planned changes (dry run):")
+	}
+	for _, a := range plan.Actions {
+		line := fmt.Sprintf("  %-9s %s -> %s  %s", a.Kind, a.From, a.To, a.Title)
+		if a.Reason != "" {
+			line += " (" + a.Reason + ")"
+		}
+		if a.Error != "" {
+			line += "\n      failed: " + a.Error
+		}
+		fmt.Println(line)
+	}
+
+	format := "%d created, %d updated, %d completed, %d conflicts\n"
+	if dryRun {
+		format = "%d to create, %d to update, %d to complete, %d conflicts\n"
+	}
+	fmt.Printf(format,
+		plan.Count(syncer.ActionCreate), plan.Count(syncer.ActionUpdate),
+		plan.Count(syncer.ActionComplete), plan.Count(syncer.ActionConflict))
+}
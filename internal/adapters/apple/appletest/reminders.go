@@ -0,0 +1,266 @@
+package appletest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Reminders is a stateful fake of the Reminders app. It reads the request
+// the adapter embeds at the top of each script and applies it to lists
+// kept in memory, printing what the real script would, so a store backed
+// by it can run the protocol conformance suite:
+//
+//	store := apple.New("Recall", apple.WithRunner(appletest.NewReminders()))
+type Reminders struct {
+	mu    sync.Mutex
+	lists map[string][]*Reminder
+	next  int
+}
+
+// Reminder is a reminder held by Reminders. Dates are nil when unset.
+type Reminder struct {
+	ID               string
+	Name             string
+	Body             string
+	Completed        bool
+	CompletionDate   *time.Time
+	DueDate          *time.Time
+	Priority         int
+	CreationDate     time.Time
+	ModificationDate time.Time
+	Flagged          bool
+}
+
+// NewReminders returns a fake with no lists.
+func NewReminders() *Reminders {
+	return &Reminders{lists: map[string][]*Reminder{}}
+}
+
+// input mirrors the request the adapter sends.
+type input struct {
+	Op       string  `json:"op"`
+	List     string  `json:"list"`
+	ID       string  `json:"id"`
+	Reminder *fields `json:"reminder"`
+	Where    *where  `json:"where"`
+}
+
+type fields struct {
+	Name      string  `json:"name"`
+	Body      string  `json:"body"`
+	DueDate   *string `json:"dueDate"`
+	Priority  int     `json:"priority"`
+	Completed bool    `json:"completed"`
+}
+
+type where struct {
+	Completed *bool   `json:"completed"`
+	DueAfter  *string `json:"dueAfter"`
+	DueBefore *string `json:"dueBefore"`
+}
+
+// output is a reminder as the script prints it.
+type output struct {
+	ID               string  `json:"id"`
+	Name             string  `json:"name"`
+	Body             string  `json:"body"`
+	Completed        bool    `json:"completed"`
+	CompletionDate   *string `json:"completionDate"`
+	DueDate          *string `json:"dueDate"`
+	Priority         int     `json:"priority"`
+	CreationDate     *string `json:"creationDate"`
+	ModificationDate *string `json:"modificationDate"`
+	Flagged          bool    `json:"flagged"`
+	List             string  `json:"list"`
+}
+
+// Run implements apple.ScriptRunner.
+func (f *Reminders) Run(ctx context.Context, script string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	line, _, _ := strings.Cut(script, "\n")
+	raw, ok := strings.CutPrefix(line, "const input = ")
+	if !ok {
+		return "", fmt.Errorf("script does not start with an input constant")
+	}
+	var in input
+	if err := json.Unmarshal([]byte(strings.TrimSuffix(raw, ";")), &in); err != nil {
+		return "", fmt.Errorf("invalid script input: %w", err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	result, err := f.run(&in)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return "", err
+	}
+	return string(data) + "\n", nil
+}
+
+func (f *Reminders) run(in *input) (any, error) {
+	switch in.Op {
+	case "list":
+		return f.list(in)
+	case "add":
+		if in.Reminder == nil {
+			return nil, fmt.Errorf("add without a reminder")
+		}
+		f.next++
+		now := time.Now()
+		r := &Reminder{
+			ID:           fmt.Sprintf("x-apple-reminder://FAKE-%04d", f.next),
+			CreationDate: now,
+		}
+		if err := apply(r, in.Reminder, now); err != nil {
+			return nil, err
+		}
+		f.lists[in.List] = append(f.lists[in.List], r)
+		return r.output(in.List), nil
+	}
+
+	r := f.find(in.List, in.ID)
+	if r == nil {
+		return nil, nil
+	}
+	now := time.Now()
+	switch in.Op {
+	case "get":
+	case "update":
+		if in.Reminder == nil {
+			return nil, fmt.Errorf("update without a reminder")
+		}
+		if err := apply(r, in.Reminder, now); err != nil {
+			return nil, err
+		}
+	case "complete":
+		r.Completed, r.CompletionDate, r.ModificationDate = true, &now, now
+	case "delete":
+		list := f.lists[in.List]
+		for i, lr := range list {
+			if lr == r {
+				f.lists[in.List] = append(list[:i], list[i+1:]...)
+				break
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unknown op %s", in.Op)
+	}
+	return r.output(in.List), nil
+}
+
+// list applies the where clause as Reminders' whose does: reminders
+// without a due date never match a due date condition.
+func (f *Reminders) list(in *input) ([]*output, error) {
+	var after, before *time.Time
+	w := in.Where
+	if w == nil {
+		w = &where{}
+	}
+	for _, v := range []struct {
+		s   *string
+		dst **time.Time
+	}{{w.DueAfter, &after}, {w.DueBefore, &before}} {
+		if v.s == nil {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, *v.s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %q: %w", *v.s, err)
+		}
+		*v.dst = &t
+	}
+
+	out := []*output{}
+	for _, r := range f.lists[in.List] {
+		switch {
+		case w.Completed != nil && r.Completed != *w.Completed:
+			continue
+		case after != nil && (r.DueDate == nil || r.DueDate.Before(*after)):
+			continue
+		case before != nil && (r.DueDate == nil || !r.DueDate.Before(*before)):
+			continue
+		}
+		out = append(out, r.output(in.List))
+	}
+	return out, nil
+}
+
+func (f *Reminders) find(list, id string) *Reminder {
+	for _, r := range f.lists[list] {
+		if r.ID == id {
+			return r
+		}
+	}
+	return nil
+}
+
+// Find returns a copy of the reminder with the given id property in any
+// list.
+func (f *Reminders) Find(id string) (Reminder, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for list := range f.lists {
+		if r := f.find(list, id); r != nil {
+			return *r, true
+		}
+	}
+	return Reminder{}, false
+}
+
+// apply sets the written properties, as the script's apply does.
+func apply(r *Reminder, fl *fields, now time.Time) error {
+	r.Name, r.Body, r.Priority = fl.Name, fl.Body, fl.Priority
+	r.DueDate = nil
+	if fl.DueDate != nil {
+		due, err := time.Parse(time.RFC3339, *fl.DueDate)
+		if err != nil {
+			return fmt.Errorf("invalid dueDate %q: %w", *fl.DueDate, err)
+		}
+		r.DueDate = &due
+	}
+	if r.Completed != fl.Completed {
+		r.Completed = fl.Completed
+		r.CompletionDate = nil
+		if fl.Completed {
+			r.CompletionDate = &now
+		}
+	}
+	r.ModificationDate = now
+	return nil
+}
+
+func (r *Reminder) output(list string) *output {
+	return &output{
+		ID:               r.ID,
+		Name:             r.Name,
+		Body:             r.Body,
+		Completed:        r.Completed,
+		CompletionDate:   iso(r.CompletionDate),
+		DueDate:          iso(r.DueDate),
+		Priority:         r.Priority,
+		CreationDate:     iso(&r.CreationDate),
+		ModificationDate: iso(&r.ModificationDate),
+		Flagged:          r.Flagged,
+		List:             list,
+	}
+}
+
+// iso formats t like JavaScript's toISOString.
+func iso(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	s := t.UTC().Format("2006-01-02T15:04:05.000Z")
+	return &s
+}
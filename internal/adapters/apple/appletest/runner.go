@@ -0,0 +1,78 @@
+// Package appletest provides fake apple.ScriptRunners for tests, so the
+// adapter can be tested without macOS. Runner records every script the
+// adapter generates and answers with canned outputs:
+//
+//	runner := &appletest.Runner{}
+//	runner.Reply(`{"id": "x-apple-reminder://5F3C", "name": "Call mom"}`)
+//	store := apple.New("Recall", apple.WithRunner(runner))
+//
+// Reminders instead keeps reminders in memory and answers like the
+// Reminders app would.
+package appletest
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// Runner is a recording apple.ScriptRunner. Replies are used in the order
+// they were queued; once they run out, scripts print nothing.
+type Runner struct {
+	mu      sync.Mutex
+	scripts []string
+	replies []reply
+}
+
+type reply struct {
+	output string
+	err    error
+}
+
+// Reply queues the output of the next script.
+func (r *Runner) Reply(output string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.replies = append(r.replies, reply{output: output})
+}
+
+// Fail makes the next script fail with err.
+func (r *Runner) Fail(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.replies = append(r.replies, reply{err: err})
+}
+
+// Run implements apple.ScriptRunner.
+func (r *Runner) Run(ctx context.Context, script string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.scripts = append(r.scripts, script)
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	if len(r.replies) == 0 {
+		return "", nil
+	}
+	next := r.replies[0]
+	r.replies = r.replies[1:]
+	return next.output, next.err
+}
+
+// Scripts returns the scripts run so far.
+func (r *Runner) Scripts() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.scripts)
+}
+
+// Last returns the most recent script, or "" if none ran.
+func (r *Runner) Last() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.scripts) == 0 {
+		return ""
+	}
+	return r.scripts[len(r.scripts)-1]
+}
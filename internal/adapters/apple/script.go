@@ -0,0 +1,213 @@
+package apple
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// request is the input to jxaProgram, embedded in the script as a JSON
+// literal so no user text is ever spliced into code.
+type request struct {
+	Op       string       `json:"op"` // list, get, add, update, delete or complete
+	List     string       `json:"list"`
+	ID       string       `json:"id,omitempty"`
+	Reminder *appleFields `json:"reminder,omitempty"`
+	Where    *where       `json:"where,omitempty"`
+}
+
+// where narrows a list in the Reminders app itself, through a whose
+// clause, so only matching reminders are sent back. Dates are ISO-8601.
+type where struct {
+	Completed *bool   `json:"completed,omitempty"`
+	DueAfter  *string `json:"dueAfter,omitempty"`
+	DueBefore *string `json:"dueBefore,omitempty"`
+}
+
+// appleFields are the properties written on add and update.
+type appleFields struct {
+	Name      string  `json:"name"`
+	Body      string  `json:"body"`
+	DueDate   *string `json:"dueDate"`
+	Priority  int     `json:"priority"`
+	Completed bool    `json:"completed"`
+}
+
+// appleReminder is a reminder as jxaProgram prints it. Dates are ISO-8601
+// in UTC, or null.
+type appleReminder struct {
+	ID               string  `json:"id"`
+	Name             string  `json:"name"`
+	Body             string  `json:"body"`
+	Completed        bool    `json:"completed"`
+	CompletionDate   *string `json:"completionDate"`
+	DueDate          *string `json:"dueDate"`
+	Priority         int     `json:"priority"`
+	CreationDate     *string `json:"creationDate"`
+	ModificationDate *string `json:"modificationDate"`
+	Flagged          bool    `json:"flagged"`
+	List             string  `json:"list"`
+}
+
+// call runs jxaProgram for req and decodes its JSON output into out. It
+// reports false when the script printed null, meaning the reminder was
+// not found.
+func (s *Store) call(ctx context.Context, req request, out any) (bool, error) {
+	req.List = s.listName
+	input, err := json.Marshal(req)
+	if err != nil {
+		return false, fmt.Errorf("marshaling script input: %w", err)
+	}
+
+	output, err := s.runner.Run(ctx, "const input = "+string(input)+";\n"+jxaProgram)
+	if err != nil {
+		return false, err
+	}
+
+	output = strings.TrimSpace(output)
+	if output == "" || output == "null" {
+		return false, nil
+	}
+	if err := json.Unmarshal([]byte(output), out); err != nil {
+		return false, fmt.Errorf("parsing Reminders output: %w", err)
+	}
+	return true, nil
+}
+
+// jxaProgram is the JavaScript for Automation run for every operation.
+// It reads the input constant prepended by call and prints JSON. Listing
+// applies the where clause and then fetches each property for all
+// matching reminders at once, which is far faster than one Apple Event
+// per reminder.
+const jxaProgram = `
+const app = Application("Reminders");
+
+function iso(d) {
+	return d ? d.toISOString() : null;
+}
+
+function findList(create) {
+	const lists = app.lists.whose({ name: input.list });
+	if (lists.length > 0) return lists[0];
+	if (!create) return null;
+	app.lists.push(app.List({ name: input.list }));
+	return app.lists.whose({ name: input.list })[0];
+}
+
+function findReminder() {
+	const list = findList(false);
+	if (!list) return null;
+	const matches = list.reminders.whose({ id: input.id });
+	return matches.length > 0 ? matches[0] : null;
+}
+
+function flagged(r) {
+	try {
+		return r.flagged();
+	} catch (e) {
+		return false;
+	}
+}
+
+function record(r) {
+	return {
+		id: r.id(),
+		name: r.name(),
+		body: r.body() || "",
+		completed: r.completed(),
+		completionDate: iso(r.completionDate()),
+		dueDate: iso(r.dueDate()),
+		priority: r.priority(),
+		creationDate: iso(r.creationDate()),
+		modificationDate: iso(r.modificationDate()),
+		flagged: flagged(r),
+		list: input.list,
+	};
+}
+
+function query(list) {
+	const w = input.where || {};
+	const conds = [];
+	if (w.completed !== undefined) conds.push({ completed: w.completed });
+	if (w.dueAfter) conds.push({ dueDate: { _greaterThanEquals: new Date(w.dueAfter) } });
+	if (w.dueBefore) conds.push({ dueDate: { _lessThan: new Date(w.dueBefore) } });
+	if (conds.length === 0) return list.reminders;
+	return list.reminders.whose(conds.length === 1 ? conds[0] : { _and: conds });
+}
+
+function listAll() {
+	const list = findList(false);
+	if (!list) return [];
+	const rs = query(list);
+	const ids = rs.id();
+	const names = rs.name();
+	const bodies = rs.body();
+	const completed = rs.completed();
+	const completionDates = rs.completionDate();
+	const dueDates = rs.dueDate();
+	const priorities = rs.priority();
+	const created = rs.creationDate();
+	const modified = rs.modificationDate();
+	let flags = [];
+	try {
+		flags = rs.flagged();
+	} catch (e) {}
+	return ids.map((id, i) => ({
+		id: id,
+		name: names[i],
+		body: bodies[i] || "",
+		completed: completed[i],
+		completionDate: iso(completionDates[i]),
+		dueDate: iso(dueDates[i]),
+		priority: priorities[i],
+		creationDate: iso(created[i]),
+		modificationDate: iso(modified[i]),
+		flagged: flags[i] || false,
+		list: input.list,
+	}));
+}
+
+function apply(r, f) {
+	r.name = f.name;
+	r.body = f.body;
+	r.priority = f.priority;
+	r.dueDate = f.dueDate ? new Date(f.dueDate) : null;
+	if (r.completed() !== f.completed) r.completed = f.completed;
+}
+
+function run() {
+	switch (input.op) {
+		case "list":
+			return listAll();
+		case "add": {
+			const list = findList(true);
+			const r = app.Reminder({ name: input.reminder.name });
+			list.reminders.push(r);
+			apply(r, input.reminder);
+			return record(r);
+		}
+	}
+
+	const r = findReminder();
+	if (!r) return null;
+	switch (input.op) {
+		case "get":
+			return record(r);
+		case "update":
+			apply(r, input.reminder);
+			return record(r);
+		case "complete":
+			r.completed = true;
+			return record(r);
+		case "delete": {
+			const before = record(r);
+			app.delete(r);
+			return before;
+		}
+	}
+	throw new Error("unknown op " + input.op);
+}
+
+JSON.stringify(run());
+`
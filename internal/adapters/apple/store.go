@@ -2,248 +2,412 @@ package apple
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"os/exec"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/shaneoxm/recall/internal/protocol"
 )
 
-var ErrNotFound = errors.New("reminder not found")
+var ErrNotFound = fmt.Errorf("reminder %w", protocol.ErrNotFound)
+
+// idScheme prefixes the id property of every reminder. IDs are stored
+// without it so they can be typed and prefix-matched like other backends'.
+const idScheme = "x-apple-reminder://"
 
 // Store implements protocol.Store using Apple Reminders via osascript.
 type Store struct {
 	listName string
+	runner   ScriptRunner
+}
+
+// ScriptRunner runs a JavaScript for Automation script and returns what
+// it printed.
+type ScriptRunner interface {
+	Run(ctx context.Context, script string) (string, error)
+}
+
+// Osascript runs scripts with the osascript command. It only works on
+// macOS.
+type Osascript struct{}
+
+// Run implements ScriptRunner.
+func (Osascript) Run(ctx context.Context, script string) (string, error) {
+	cmd := exec.CommandContext(ctx, "osascript", "-l", "JavaScript", "-e", script)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("osascript error: %w: %s", err, string(out))
+	}
+	return string(out), nil
+}
+
+// Option configures a Store.
+type Option func(*Store)
+
+// WithRunner sets how scripts are run, such as an appletest.Runner in
+// tests. The default is Osascript.
+func WithRunner(r ScriptRunner) Option {
+	return func(s *Store) {
+		s.runner = r
+	}
 }
 
 // New creates a new Apple Reminders store.
 // If listName is empty, uses "Recall" as the default list.
-func New(listName string) *Store {
+func New(listName string, opts ...Option) *Store {
 	if listName == "" {
 		listName = "Recall"
 	}
-	return &Store{listName: listName}
+	s := &Store{listName: listName, runner: Osascript{}}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
-// Add creates a new reminder in Apple Reminders.
+// Add creates a new reminder in Apple Reminders, creating the list if
+// needed, and sets reminder.ID to the new reminder's id.
 func (s *Store) Add(ctx context.Context, reminder *protocol.Reminder) error {
-	script := s.buildAddScript(reminder)
-	_, err := s.runScript(ctx, script)
-	return err
+	f, err := fields(reminder)
+	if err != nil {
+		return err
+	}
+	var created appleReminder
+	found, err := s.call(ctx, request{Op: "add", Reminder: f}, &created)
+	if err != nil {
+		return err
+	}
+	if !found || created.ID == "" {
+		return fmt.Errorf("reminders did not return an id for %q", reminder.Title)
+	}
+
+	r := s.toReminder(&created)
+	reminder.ID = r.ID
+	reminder.Metadata = r.Metadata
+	if !r.CreatedAt.IsZero() {
+		reminder.CreatedAt = r.CreatedAt
+	}
+	if !r.UpdatedAt.IsZero() {
+		reminder.UpdatedAt = r.UpdatedAt
+	}
+	return nil
 }
 
-// Get retrieves a reminder by ID (title match for Apple Reminders).
+// Get retrieves a reminder by ID.
 func (s *Store) Get(ctx context.Context, id string) (*protocol.Reminder, error) {
-	// Apple Reminders doesn't expose IDs easily via AppleScript
-	// We search by title as a workaround
-	reminders, err := s.List(ctx, &protocol.ListFilter{Search: id})
+	var ar appleReminder
+	found, err := s.call(ctx, request{Op: "get", ID: appleID(id)}, &ar)
 	if err != nil {
 		return nil, err
 	}
-	for _, r := range reminders {
-		if r.ID == id || r.Title == id {
-			return r, nil
-		}
+	if !found {
+		return nil, ErrNotFound
 	}
-	return nil, ErrNotFound
+	return s.toReminder(&ar), nil
 }
 
-// List returns all reminders from the Apple Reminders list.
+// List returns the reminders in the Apple Reminders list that match
+// filter. Completion and due dates are filtered by Reminders itself; tags
+// and search are matched here.
 func (s *Store) List(ctx context.Context, filter *protocol.ListFilter) ([]*protocol.Reminder, error) {
-	script := fmt.Sprintf(`
-tell application "Reminders"
-	set output to ""
-	try
-		set reminderList to list "%s"
-		repeat with r in reminders of reminderList
-			set rName to name of r
-			set rBody to body of r
-			if rBody is missing value then set rBody to ""
-			set rCompleted to completed of r
-			set rDueDate to ""
-			try
-				set rDueDate to due date of r as string
-			end try
-			set rPriority to priority of r
-			set output to output & rName & "|||" & rBody & "|||" & rCompleted & "|||" & rDueDate & "|||" & rPriority & "
-"
-		end repeat
-	end try
-	return output
-end tell`, s.listName)
-
-	out, err := s.runScript(ctx, script)
-	if err != nil {
+	var all []appleReminder
+	if _, err := s.call(ctx, request{Op: "list", Where: whereFor(filter)}, &all); err != nil {
 		return nil, err
 	}
 
-	return s.parseReminders(out, filter), nil
+	var reminders []*protocol.Reminder
+	for i := range all {
+		r := s.toReminder(&all[i])
+		if filter.Matches(r) {
+			reminders = append(reminders, r)
+		}
+	}
+	return reminders, nil
 }
 
-// Update modifies an existing reminder.
+// Update sets the reminder's properties in place, so its id and creation
+// date are kept.
 func (s *Store) Update(ctx context.Context, reminder *protocol.Reminder) error {
-	// For simplicity, we delete and re-add
-	if err := s.Delete(ctx, reminder.Title); err != nil && !errors.Is(err, ErrNotFound) {
+	f, err := fields(reminder)
+	if err != nil {
 		return err
 	}
-	return s.Add(ctx, reminder)
+	return s.mutate(ctx, request{Op: "update", ID: appleID(reminder.ID), Reminder: f})
 }
 
-// Delete removes a reminder by title.
+// Delete removes a reminder by ID.
 func (s *Store) Delete(ctx context.Context, id string) error {
-	script := fmt.Sprintf(`
-tell application "Reminders"
-	try
-		set reminderList to list "%s"
-		repeat with r in reminders of reminderList
-			if name of r is "%s" then
-				delete r
-				return "deleted"
-			end if
-		end repeat
-	end try
-	return "not found"
-end tell`, s.listName, escapeAppleScript(id))
-
-	out, err := s.runScript(ctx, script)
+	return s.mutate(ctx, request{Op: "delete", ID: appleID(id)})
+}
+
+// Complete marks a reminder as completed. Reminders cannot repeat a rule
+// kept in the body, so a recurring reminder is rolled forward here and
+// written back instead.
+func (s *Store) Complete(ctx context.Context, id string) error {
+	r, err := s.Get(ctx, id)
 	if err != nil {
 		return err
 	}
-	if strings.TrimSpace(out) == "not found" {
-		return ErrNotFound
+	if r.Recurrence == nil {
+		return s.mutate(ctx, request{Op: "complete", ID: appleID(id)})
 	}
-	return nil
+	r.Complete()
+	return s.Update(ctx, r)
 }
 
-// Complete marks a reminder as completed.
-func (s *Store) Complete(ctx context.Context, id string) error {
-	script := fmt.Sprintf(`
-tell application "Reminders"
-	try
-		set reminderList to list "%s"
-		repeat with r in reminders of reminderList
-			if name of r is "%s" then
-				set completed of r to true
-				return "completed"
-			end if
-		end repeat
-	end try
-	return "not found"
-end tell`, s.listName, escapeAppleScript(id))
-
-	out, err := s.runScript(ctx, script)
+// mutate runs an operation on a single reminder, returning ErrNotFound
+// when there is no such reminder in the list.
+func (s *Store) mutate(ctx context.Context, req request) error {
+	var ar appleReminder
+	found, err := s.call(ctx, req, &ar)
 	if err != nil {
 		return err
 	}
-	if strings.TrimSpace(out) == "not found" {
+	if !found {
 		return ErrNotFound
 	}
 	return nil
 }
 
-func (s *Store) buildAddScript(r *protocol.Reminder) string {
-	props := []string{fmt.Sprintf(`name:"%s"`, escapeAppleScript(r.Title))}
+// whereFor returns the part of filter Reminders can apply, or nil when
+// there is none.
+func whereFor(filter *protocol.ListFilter) *where {
+	if filter == nil {
+		return nil
+	}
 
-	if r.Notes != "" {
-		props = append(props, fmt.Sprintf(`body:"%s"`, escapeAppleScript(r.Notes)))
+	w := &where{}
+	if !filter.IncludeCompleted {
+		open := false
+		w.Completed = &open
+	}
+	if filter.DueAfter != nil {
+		v := filter.DueAfter.UTC().Format(time.RFC3339)
+		w.DueAfter = &v
+	}
+	if filter.DueBefore != nil {
+		v := filter.DueBefore.UTC().Format(time.RFC3339)
+		w.DueBefore = &v
 	}
+	if *w == (where{}) {
+		return nil
+	}
+	return w
+}
 
-	if r.Due != nil {
-		props = append(props, fmt.Sprintf(`due date:date "%s"`, r.Due.Format("January 2, 2006 3:04:05 PM")))
+// fields converts a reminder to the properties Reminders stores. Tags
+// that would not read back as the same hashtag are rejected.
+func fields(r *protocol.Reminder) (*appleFields, error) {
+	for _, tag := range r.Tags {
+		if got := hashtags("#" + tag); len(got) != 1 || got[0] != tag {
+			return nil, fmt.Errorf("%w: tag %q cannot be kept as a hashtag; tags must start with a letter and contain no spaces", protocol.ErrInvalid, tag)
+		}
 	}
 
-	if r.Priority > 0 {
-		// Apple priority: 0=none, 1=high, 5=medium, 9=low (inverse of ours)
-		applePriority := map[int]int{1: 9, 2: 5, 3: 1}[r.Priority]
-		props = append(props, fmt.Sprintf(`priority:%d`, applePriority))
+	f := &appleFields{
+		Name:      r.Title,
+		Body:      buildBody(r.Notes, r.Links, r.Recurrence, r.Tags),
+		Priority:  applePriority(r.Priority),
+		Completed: r.Completed,
+	}
+	if r.Due != nil {
+		due := r.Due.UTC().Format(time.RFC3339)
+		f.DueDate = &due
 	}
+	return f, nil
+}
 
-	return fmt.Sprintf(`
-tell application "Reminders"
-	try
-		set reminderList to list "%s"
-	on error
-		make new list with properties {name:"%s"}
-		set reminderList to list "%s"
-	end try
-	tell reminderList
-		make new reminder with properties {%s}
-	end tell
-end tell`, s.listName, s.listName, s.listName, strings.Join(props, ", "))
+// applePriority converts our priority to Apple's scale: 0=none, 1=high,
+// 5=medium, 9=low (inverse of ours).
+func applePriority(p int) int {
+	return map[int]int{1: 9, 2: 5, 3: 1}[p]
 }
 
-func (s *Store) runScript(ctx context.Context, script string) (string, error) {
-	cmd := exec.CommandContext(ctx, "osascript", "-e", script)
-	out, err := cmd.CombinedOutput()
-	if err != nil {
-		return "", fmt.Errorf("osascript error: %w: %s", err, string(out))
+// appleID returns the full id property for a stored ID.
+func appleID(id string) string {
+	if strings.HasPrefix(id, idScheme) {
+		return id
 	}
-	return string(out), nil
+	return idScheme + id
 }
 
-func (s *Store) parseReminders(output string, filter *protocol.ListFilter) []*protocol.Reminder {
-	var reminders []*protocol.Reminder
-	lines := strings.Split(strings.TrimSpace(output), "\n")
+// shortID strips the x-apple-reminder:// scheme from an id property.
+func shortID(id string) string {
+	return strings.TrimPrefix(id, idScheme)
+}
 
-	for _, line := range lines {
-		if line == "" {
-			continue
-		}
-		parts := strings.Split(line, "|||")
-		if len(parts) < 5 {
-			continue
-		}
+func (s *Store) toReminder(ar *appleReminder) *protocol.Reminder {
+	r := &protocol.Reminder{
+		ID:        shortID(ar.ID),
+		Title:     ar.Name,
+		Completed: ar.Completed,
+		Due:       parseDate(ar.DueDate),
+	}
+	r.Notes, r.Links, r.Recurrence, r.Tags = parseBody(ar.Body)
 
-		r := &protocol.Reminder{
-			ID:        parts[0], // Use title as ID for Apple Reminders
-			Title:     parts[0],
-			Notes:     parts[1],
-			Completed: parts[2] == "true",
-			CreatedAt: time.Now(), // Apple doesn't expose creation date easily
-			UpdatedAt: time.Now(),
-		}
+	if t := parseDate(ar.CreationDate); t != nil {
+		r.CreatedAt = *t
+	}
+	if t := parseDate(ar.ModificationDate); t != nil {
+		r.UpdatedAt = *t
+	}
+	if r.Completed {
+		r.CompletedAt = parseDate(ar.CompletionDate)
+	}
 
-		// Parse due date
-		if parts[3] != "" {
-			if t, err := time.Parse("Monday, January 2, 2006 at 3:04:05 PM", parts[3]); err == nil {
-				r.Due = &t
-			}
-		}
+	// Apple uses 1-4 for high, 5 for medium and 6-9 for low.
+	switch {
+	case ar.Priority >= 1 && ar.Priority <= 4:
+		r.Priority = 3
+	case ar.Priority == 5:
+		r.Priority = 2
+	case ar.Priority >= 6 && ar.Priority <= 9:
+		r.Priority = 1
+	}
 
-		// Parse priority (convert from Apple's scale)
-		switch parts[4] {
-		case "1":
-			r.Priority = 3 // high
-		case "5":
-			r.Priority = 2 // medium
-		case "9":
-			r.Priority = 1 // low
-		}
+	list := ar.List
+	if list == "" {
+		list = s.listName
+	}
+	r.Metadata = map[string]string{"list": list, "url": appleID(ar.ID)}
+	if ar.Flagged {
+		r.Metadata["flagged"] = "true"
+	}
+	return r
+}
 
-		// Apply filters
-		if filter != nil {
-			if !filter.IncludeCompleted && r.Completed {
-				continue
-			}
-			if filter.Search != "" {
-				search := strings.ToLower(filter.Search)
-				if !strings.Contains(strings.ToLower(r.Title), search) &&
-					!strings.Contains(strings.ToLower(r.Notes), search) {
-					continue
-				}
-			}
+// parseDate reads an ISO-8601 date from a script, in local time. Dates
+// that do not parse are treated as unset rather than failing the whole
+// listing.
+func parseDate(v *string) *time.Time {
+	if v == nil || *v == "" {
+		return nil
+	}
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02"} {
+		if t, err := time.Parse(layout, *v); err == nil {
+			t = t.Local()
+			return &t
 		}
+	}
+	return nil
+}
+
+// Reminders' native tags cannot be read or written by scripts, so tags
+// are kept as hashtags on the last line of the body, as people often
+// write them by hand. Links and the recurrence rule, which Reminders has
+// no scriptable property for, go before it:
+//
+//	Check the error handling
+//
+//	Links:
+//	- https://github.com/shaneoxm/recall/pull/1
+//
+//	RRULE:FREQ=WEEKLY;BYDAY=MO
+//
+//	#work #code
+
+// buildBody returns notes with a block of links, an RRULE line and a
+// hashtag line for tags appended.
+func buildBody(notes string, links []string, rec *protocol.Recurrence, tags []string) string {
+	var parts []string
+	if notes != "" {
+		parts = append(parts, notes)
+	}
+	if len(links) > 0 {
+		parts = append(parts, "Links:\n- "+strings.Join(links, "\n- "))
+	}
+	if rec != nil {
+		parts = append(parts, "RRULE:"+rec.String())
+	}
+	if len(tags) > 0 {
+		parts = append(parts, "#"+strings.Join(tags, " #"))
+	}
+	return strings.Join(parts, "\n\n")
+}
 
-		reminders = append(reminders, r)
+// parseBody splits a body into notes, links, recurrence and tags. Tags
+// come only from a trailing line made of hashtags, which is removed from
+// the notes; hashtags elsewhere are part of the notes, so writing the
+// reminder back does not copy them onto the tag line.
+func parseBody(body string) (string, []string, *protocol.Recurrence, []string) {
+	notes := strings.TrimRight(body, "\n ")
+	var tags []string
+
+	i := strings.LastIndex(notes, "\n")
+	if last := notes[i+1:]; isHashtagLine(last) {
+		tags = hashtags(last)
+		notes = strings.TrimRight(notes[:i+1], "\n ")
+	} else {
+		notes = body
 	}
 
-	return reminders
+	notes, rec := parseRRule(notes)
+	notes, links := parseLinks(notes)
+	return notes, links, rec, tags
 }
 
-func escapeAppleScript(s string) string {
-	s = strings.ReplaceAll(s, "\\", "\\\\")
-	s = strings.ReplaceAll(s, "\"", "\\\"")
-	return s
+// parseRRule splits the RRULE line written by buildBody off the end of
+// notes. Notes without a valid one are returned as they are.
+func parseRRule(notes string) (string, *protocol.Recurrence) {
+	rest, line := "", strings.TrimRight(notes, "\n ")
+	if i := strings.LastIndex(line, "\n\n"); i >= 0 {
+		rest, line = line[:i], line[i+2:]
+	}
+	if !strings.HasPrefix(line, "RRULE:") {
+		return notes, nil
+	}
+	rec, err := protocol.ParseRRule(line)
+	if err != nil {
+		return notes, nil
+	}
+	return rest, rec
+}
+
+// parseLinks splits the "Links:" block written by buildBody off the end
+// of notes. Notes without one are returned as they are.
+func parseLinks(notes string) (string, []string) {
+	rest, block := "", strings.TrimRight(notes, "\n ")
+	if i := strings.LastIndex(block, "\n\nLinks:\n"); i >= 0 {
+		rest, block = block[:i], block[i+2:]
+	}
+
+	items, ok := strings.CutPrefix(block, "Links:\n")
+	if !ok {
+		return notes, nil
+	}
+	var links []string
+	for _, line := range strings.Split(items, "\n") {
+		link, ok := strings.CutPrefix(line, "- ")
+		if !ok || link == "" {
+			return notes, nil
+		}
+		links = append(links, link)
+	}
+	return rest, links
+}
+
+func isHashtagLine(line string) bool {
+	words := strings.Fields(line)
+	return len(words) > 0 && len(hashtags(line)) == len(words)
+}
+
+// hashtags returns the words of s that are hashtags: a # followed by a
+// letter, so issue numbers like #12 are not mistaken for tags.
+func hashtags(s string) []string {
+	var tags []string
+	for _, word := range strings.Fields(s) {
+		word = strings.TrimRight(word, ".,;:!?)")
+		tag, ok := strings.CutPrefix(word, "#")
+		if !ok || tag == "" {
+			continue
+		}
+		if r, _ := utf8.DecodeRuneInString(tag); !unicode.IsLetter(r) {
+			continue
+		}
+		tags = append(tags, tag)
+	}
+	return tags
 }
@@ -0,0 +1,478 @@
+package apple
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shaneoxm/recall/internal/adapters/apple/appletest"
+	"github.com/shaneoxm/recall/internal/protocol"
+	"github.com/shaneoxm/recall/internal/protocol/storetest"
+)
+
+func newTestStore(t *testing.T) (*Store, *appletest.Runner) {
+	t.Helper()
+	runner := &appletest.Runner{}
+	return New("Recall", WithRunner(runner)), runner
+}
+
+// scriptInput decodes the input constant at the top of a generated script.
+func scriptInput(t *testing.T, script string) request {
+	t.Helper()
+	line, _, _ := strings.Cut(script, "\n")
+	raw, ok := strings.CutPrefix(line, "const input = ")
+	if !ok {
+		t.Fatalf("script does not start with an input constant:\n%s", script)
+	}
+	var req request
+	if err := json.Unmarshal([]byte(strings.TrimSuffix(raw, ";")), &req); err != nil {
+		t.Fatalf("invalid script input %s: %v", raw, err)
+	}
+	return req
+}
+
+const listOutput = `[
+	{"id": "x-apple-reminder://A1", "name": "Dentist", "body": "", "completed": false,
+	 "dueDate": "2026-01-15T09:00:00.000Z", "priority": 0, "list": "Recall"},
+	{"id": "x-apple-reminder://B2", "name": "Groceries", "body": "milk|||eggs\nbread",
+	 "completed": false, "dueDate": null, "priority": 5, "flagged": true, "list": "Recall"},
+	{"id": "x-apple-reminder://C3", "name": "Taxes", "body": "", "completed": true,
+	 "completionDate": "2026-01-14T18:30:00.000Z", "dueDate": "2026-01-14T17:00:00.000Z",
+	 "priority": 1, "creationDate": "2026-01-02T08:00:00.000Z",
+	 "modificationDate": "2026-01-14T18:30:00.000Z", "list": "Recall"}
+]`
+
+func TestStore_List(t *testing.T) {
+	store, runner := newTestStore(t)
+	runner.Reply(listOutput)
+
+	all, err := store.List(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("failed to list: %v", err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("expected 3 reminders, got %d", len(all))
+	}
+	if req := scriptInput(t, runner.Last()); req.Op != "list" || req.List != "Recall" {
+		t.Errorf("expected a list of Recall, got %+v", req)
+	}
+
+	groceries := all[1]
+	if groceries.ID != "B2" || groceries.Notes != "milk|||eggs\nbread" {
+		t.Errorf("expected notes to survive intact, got %q", groceries.Notes)
+	}
+	if groceries.Priority != 2 || groceries.Metadata["flagged"] != "true" {
+		t.Errorf("expected medium priority and flagged, got %d %v", groceries.Priority, groceries.Metadata)
+	}
+
+	taxes := all[2]
+	if !taxes.Completed || taxes.CompletedAt == nil || taxes.Priority != 3 {
+		t.Errorf("unexpected completed reminder %+v", taxes)
+	}
+	wantDue := time.Date(2026, 1, 14, 17, 0, 0, 0, time.UTC)
+	if taxes.Due == nil || !taxes.Due.Equal(wantDue) {
+		t.Errorf("expected due %s, got %v", wantDue, taxes.Due)
+	}
+	if !taxes.CreatedAt.Equal(time.Date(2026, 1, 2, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected creation date to be read, got %s", taxes.CreatedAt)
+	}
+}
+
+func TestStore_ListFilter(t *testing.T) {
+	store, runner := newTestStore(t)
+	list := func(filter *protocol.ListFilter) []*protocol.Reminder {
+		t.Helper()
+		runner.Reply(listOutput)
+		reminders, err := store.List(context.Background(), filter)
+		if err != nil {
+			t.Fatalf("failed to list: %v", err)
+		}
+		return reminders
+	}
+
+	if open := list(&protocol.ListFilter{}); len(open) != 2 {
+		t.Errorf("expected completed reminder to be excluded, got %d", len(open))
+	}
+
+	after := time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)
+	before := time.Date(2026, 1, 16, 0, 0, 0, 0, time.UTC)
+	if dated := list(&protocol.ListFilter{DueAfter: &after, DueBefore: &before}); len(dated) != 1 || dated[0].Title != "Dentist" {
+		t.Errorf("expected only Dentist in due range, got %v", dated)
+	}
+}
+
+func TestStore_ListInvalidOutput(t *testing.T) {
+	store, runner := newTestStore(t)
+	runner.Reply("execution error: Reminders got an error")
+
+	if _, err := store.List(context.Background(), nil); err == nil {
+		t.Error("expected an error for output that is not JSON")
+	}
+}
+
+func TestStore_Add(t *testing.T) {
+	store, runner := newTestStore(t)
+	runner.Reply(`{"id": "x-apple-reminder://5F3C", "name": "ignored",
+		"creationDate": "2026-03-01T10:00:00.000Z", "modificationDate": "2026-03-01T10:00:00.000Z"}`)
+
+	title := `Say "hi" to C:\Users"); app.quit(); ("`
+	r := protocol.NewReminder(title)
+	r.SetNotes("Line one\nLine two")
+	r.SetPriority(3)
+	r.SetDue(time.Date(2026, 3, 15, 9, 45, 0, 0, time.UTC))
+	if err := store.Add(context.Background(), r); err != nil {
+		t.Fatalf("failed to add: %v", err)
+	}
+
+	if r.ID != "5F3C" || r.Metadata["url"] != "x-apple-reminder://5F3C" {
+		t.Errorf("expected ID 5F3C, got %q %v", r.ID, r.Metadata)
+	}
+	if !r.CreatedAt.Equal(time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected the creation date from Reminders, got %s", r.CreatedAt)
+	}
+
+	req := scriptInput(t, runner.Last())
+	f := req.Reminder
+	if req.Op != "add" || f == nil {
+		t.Fatalf("expected an add, got %+v", req)
+	}
+	if f.Name != title || f.Body != "Line one\nLine two" || f.Priority != 1 {
+		t.Errorf("unexpected fields %+v", f)
+	}
+	if f.DueDate == nil || *f.DueDate != "2026-03-15T09:45:00Z" {
+		t.Errorf("expected an ISO due date, got %v", f.DueDate)
+	}
+}
+
+func TestStore_AddNoID(t *testing.T) {
+	store, runner := newTestStore(t)
+	runner.Reply("null")
+
+	if err := store.Add(context.Background(), protocol.NewReminder("Lost")); err == nil {
+		t.Error("expected an error when Reminders returns no id")
+	}
+}
+
+func TestStore_Get(t *testing.T) {
+	store, runner := newTestStore(t)
+	runner.Reply(`{"id": "x-apple-reminder://5F3C", "name": "Call mom", "body": "Birthday", "priority": 5}`)
+
+	r, err := store.Get(context.Background(), "5F3C")
+	if err != nil {
+		t.Fatalf("failed to get: %v", err)
+	}
+	if r.ID != "5F3C" || r.Title != "Call mom" || r.Notes != "Birthday" || r.Priority != 2 {
+		t.Errorf("unexpected reminder %+v", r)
+	}
+	if req := scriptInput(t, runner.Last()); req.Op != "get" || req.ID != "x-apple-reminder://5F3C" {
+		t.Errorf("expected a get by full id, got %+v", req)
+	}
+}
+
+func TestStore_Update(t *testing.T) {
+	store, runner := newTestStore(t)
+	runner.Reply(`{"id": "x-apple-reminder://5F3C"}`)
+
+	r := protocol.NewReminder("Call mom")
+	r.ID = "5F3C"
+	r.SetNotes("Birthday")
+	r.SetPriority(1)
+	if err := store.Update(context.Background(), r); err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+
+	if n := len(runner.Scripts()); n != 1 {
+		t.Errorf("expected the reminder to be updated in place with 1 script, got %d", n)
+	}
+	req := scriptInput(t, runner.Last())
+	if req.Op != "update" || req.ID != "x-apple-reminder://5F3C" {
+		t.Fatalf("expected an update of 5F3C, got %+v", req)
+	}
+	if f := req.Reminder; f.Name != "Call mom" || f.Body != "Birthday" || f.Priority != 9 || f.DueDate != nil || f.Completed {
+		t.Errorf("unexpected fields %+v", f)
+	}
+}
+
+func TestStore_NotFound(t *testing.T) {
+	store, runner := newTestStore(t)
+	ctx := context.Background()
+
+	runner.Reply("null\n")
+	if _, err := store.Get(ctx, "missing"); !errors.Is(err, protocol.ErrNotFound) {
+		t.Errorf("Get: expected ErrNotFound, got %v", err)
+	}
+	runner.Reply("null\n")
+	if err := store.Complete(ctx, "missing"); !errors.Is(err, protocol.ErrNotFound) {
+		t.Errorf("Complete: expected ErrNotFound, got %v", err)
+	}
+	runner.Reply("null\n")
+	if err := store.Delete(ctx, "missing"); !errors.Is(err, protocol.ErrNotFound) {
+		t.Errorf("Delete: expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestStore_RunnerError(t *testing.T) {
+	store, runner := newTestStore(t)
+	runner.Fail(errors.New("osascript error: exit status 1"))
+
+	if _, err := store.List(context.Background(), nil); err == nil {
+		t.Error("expected the runner's error to be returned")
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	want := time.Date(2026, 1, 15, 9, 0, 0, 0, time.UTC)
+	for _, v := range []string{"2026-01-15T09:00:00.000Z", "2026-01-15T09:00:00Z", "2026-01-15T10:00:00+01:00"} {
+		if got := parseDate(&v); got == nil || !got.Equal(want) {
+			t.Errorf("parseDate(%q) = %v, want %s", v, got, want)
+		}
+	}
+	for _, v := range []string{"", "Thursday, January 15, 2026 at 9:00:00 AM"} {
+		if got := parseDate(&v); got != nil {
+			t.Errorf("parseDate(%q) = %v, want nil", v, got)
+		}
+	}
+}
+
+func TestAppleID(t *testing.T) {
+	for _, id := range []string{"5F3C", "x-apple-reminder://5F3C"} {
+		if got := appleID(id); got != "x-apple-reminder://5F3C" {
+			t.Errorf("appleID(%q) = %q", id, got)
+		}
+	}
+	if got := shortID("x-apple-reminder://5F3C"); got != "5F3C" {
+		t.Errorf("shortID = %q", got)
+	}
+}
+
+func TestStore_ListWhere(t *testing.T) {
+	store, runner := newTestStore(t)
+	ctx := context.Background()
+
+	runner.Reply("[]")
+	if _, err := store.List(ctx, nil); err != nil {
+		t.Fatalf("failed to list: %v", err)
+	}
+	if req := scriptInput(t, runner.Last()); req.Where != nil {
+		t.Errorf("expected no where clause for a nil filter, got %+v", req.Where)
+	}
+
+	after := time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)
+	before := time.Date(2026, 1, 16, 0, 0, 0, 0, time.UTC)
+	runner.Reply("[]")
+	if _, err := store.List(ctx, &protocol.ListFilter{DueAfter: &after, DueBefore: &before}); err != nil {
+		t.Fatalf("failed to list: %v", err)
+	}
+	w := scriptInput(t, runner.Last()).Where
+	if w == nil || w.Completed == nil || *w.Completed {
+		t.Fatalf("expected open reminders only, got %+v", w)
+	}
+	if w.DueAfter == nil || *w.DueAfter != "2026-01-15T00:00:00Z" || w.DueBefore == nil || *w.DueBefore != "2026-01-16T00:00:00Z" {
+		t.Errorf("expected the due range in the where clause, got %+v", w)
+	}
+
+	runner.Reply("[]")
+	if _, err := store.List(ctx, &protocol.ListFilter{IncludeCompleted: true, Search: "x"}); err != nil {
+		t.Fatalf("failed to list: %v", err)
+	}
+	if req := scriptInput(t, runner.Last()); req.Where != nil {
+		t.Errorf("expected search to be matched locally, got %+v", req.Where)
+	}
+}
+
+func TestStore_ListTags(t *testing.T) {
+	store, runner := newTestStore(t)
+	runner.Reply(`[
+		{"id": "x-apple-reminder://A1", "name": "Deploy", "body": "Check dashboards\n\n#work #ops"},
+		{"id": "x-apple-reminder://B2", "name": "Garden", "body": "Ask about #Work hours"},
+		{"id": "x-apple-reminder://C3", "name": "Fix #12", "body": "See issue #12"}
+	]`)
+
+	reminders, err := store.List(context.Background(), &protocol.ListFilter{Tags: []string{"work"}})
+	if err != nil {
+		t.Fatalf("failed to list: %v", err)
+	}
+	// Hashtags inside the notes are not tags.
+	if len(reminders) != 1 || reminders[0].Title != "Deploy" {
+		t.Fatalf("expected Deploy only, got %v", reminders)
+	}
+	if reminders[0].Notes != "Check dashboards" {
+		t.Errorf("expected the hashtag line to be stripped from notes, got %q", reminders[0].Notes)
+	}
+}
+
+func TestStore_UpdateKeepsInlineHashtags(t *testing.T) {
+	fake := appletest.NewReminders()
+	store := New("Recall", WithRunner(fake))
+	ctx := context.Background()
+
+	r := protocol.NewReminder("Deploy")
+	r.SetNotes("Ask #ops about the #Work freeze")
+	r.AddTag("release")
+	if err := store.Add(ctx, r); err != nil {
+		t.Fatalf("failed to add: %v", err)
+	}
+
+	for range 2 {
+		got, err := store.Get(ctx, r.ID)
+		if err != nil {
+			t.Fatalf("failed to get: %v", err)
+		}
+		if got.Notes != "Ask #ops about the #Work freeze" || !slices.Equal(got.Tags, []string{"release"}) {
+			t.Fatalf("unexpected notes %q and tags %q", got.Notes, got.Tags)
+		}
+		if err := store.Update(ctx, got); err != nil {
+			t.Fatalf("failed to update: %v", err)
+		}
+	}
+
+	stored, _ := fake.Find(appleID(r.ID))
+	if want := "Ask #ops about the #Work freeze\n\n#release"; stored.Body != want {
+		t.Errorf("expected body %q, got %q", want, stored.Body)
+	}
+}
+
+func TestBody(t *testing.T) {
+	tests := []struct {
+		notes    string
+		links    []string
+		rule     string
+		tags     []string
+		body     string
+		readTags []string
+	}{
+		{"", nil, "", nil, "", nil},
+		{"Just notes", nil, "", nil, "Just notes", nil},
+		{"Check dashboards", nil, "", []string{"work", "ops"}, "Check dashboards\n\n#work #ops", []string{"work", "ops"}},
+		{"", nil, "", []string{"work"}, "#work", []string{"work"}},
+		{"Multi\nline", nil, "", []string{"side-project"}, "Multi\nline\n\n#side-project", []string{"side-project"}},
+		{"See PR", []string{"https://a.example", "https://b.example"}, "", []string{"code"},
+			"See PR\n\nLinks:\n- https://a.example\n- https://b.example\n\n#code", []string{"code"}},
+		{"", []string{"https://a.example"}, "", nil, "Links:\n- https://a.example", nil},
+		{"Pay rent", nil, "FREQ=MONTHLY;BYMONTHDAY=31", nil, "Pay rent\n\nRRULE:FREQ=MONTHLY;BYMONTHDAY=31", nil},
+		{"Gym", []string{"https://a.example"}, "FREQ=WEEKLY;BYDAY=MO,WE", []string{"health"},
+			"Gym\n\nLinks:\n- https://a.example\n\nRRULE:FREQ=WEEKLY;BYDAY=MO,WE\n\n#health", []string{"health"}},
+		{"", nil, "FREQ=DAILY", nil, "RRULE:FREQ=DAILY", nil},
+	}
+
+	for _, tt := range tests {
+		var rec *protocol.Recurrence
+		if tt.rule != "" {
+			rec, _ = protocol.ParseRRule(tt.rule)
+		}
+		body := buildBody(tt.notes, tt.links, rec, tt.tags)
+		if body != tt.body {
+			t.Errorf("buildBody(%q, %q, %q, %q) = %q, want %q", tt.notes, tt.links, tt.rule, tt.tags, body, tt.body)
+		}
+		notes, links, gotRec, tags := parseBody(body)
+		var rule string
+		if gotRec != nil {
+			rule = gotRec.String()
+		}
+		if notes != tt.notes || !slices.Equal(links, tt.links) || rule != tt.rule || !slices.Equal(tags, tt.readTags) {
+			t.Errorf("parseBody(%q) = %q, %q, %q, %q; want %q, %q, %q, %q", body, notes, links, rule, tags, tt.notes, tt.links, tt.rule, tt.readTags)
+		}
+	}
+
+	// A links block in the middle of the notes is left alone.
+	if notes, links, _, _ := parseBody("Links:\n- https://a.example\n\nMore notes"); links != nil || notes == "" {
+		t.Errorf("expected notes to be kept as written, got %q, %q", notes, links)
+	}
+
+	// So is an RRULE line Recall cannot read.
+	if notes, _, rec, _ := parseBody("Standup\n\nRRULE:FREQ=HOURLY"); rec != nil || notes != "Standup\n\nRRULE:FREQ=HOURLY" {
+		t.Errorf("expected notes to be kept as written, got %q, %v", notes, rec)
+	}
+}
+
+func TestStore_TagRoundTrip(t *testing.T) {
+	fake := appletest.NewReminders()
+	store := New("Recall", WithRunner(fake))
+	ctx := context.Background()
+
+	r := protocol.NewReminder("Prep")
+	r.Tags = []string{"work", "q3-goals", "café"}
+	if err := store.Add(ctx, r); err != nil {
+		t.Fatalf("failed to add: %v", err)
+	}
+	for range 2 {
+		got, err := store.Get(ctx, r.ID)
+		if err != nil {
+			t.Fatalf("failed to get: %v", err)
+		}
+		if got.Notes != "" || !slices.Equal(got.Tags, r.Tags) {
+			t.Fatalf("expected tags %q and no notes, got %q and %q", r.Tags, got.Tags, got.Notes)
+		}
+		if err := store.Update(ctx, got); err != nil {
+			t.Fatalf("failed to update: %v", err)
+		}
+	}
+
+	// Tags that would not read back as written are rejected, not renamed.
+	for _, tag := range []string{"1on1", "2024", "side project", "done!"} {
+		bad := protocol.NewReminder("Prep")
+		bad.Tags = []string{"work", tag}
+		if err := store.Add(ctx, bad); !errors.Is(err, protocol.ErrInvalid) {
+			t.Errorf("Add with tag %q: expected ErrInvalid, got %v", tag, err)
+		}
+
+		r.Tags = []string{"work", tag}
+		if err := store.Update(ctx, r); !errors.Is(err, protocol.ErrInvalid) {
+			t.Errorf("Update with tag %q: expected ErrInvalid, got %v", tag, err)
+		}
+	}
+	stored, _ := fake.Find(appleID(r.ID))
+	if want := "#work #q3-goals #café"; stored.Body != want {
+		t.Errorf("expected body %q to be unchanged, got %q", want, stored.Body)
+	}
+}
+
+func TestStore_CompleteRecurring(t *testing.T) {
+	fake := appletest.NewReminders()
+	store := New("Recall", WithRunner(fake))
+	ctx := context.Background()
+
+	due := time.Now().AddDate(0, 0, 1).Truncate(time.Second)
+	r := protocol.NewReminder("Water plants")
+	r.SetDue(due)
+	r.SetRecurrence(&protocol.Recurrence{Freq: protocol.Daily, Count: 2})
+	if err := store.Add(ctx, r); err != nil {
+		t.Fatalf("failed to add: %v", err)
+	}
+
+	if err := store.Complete(ctx, r.ID); err != nil {
+		t.Fatalf("failed to complete: %v", err)
+	}
+	got, err := store.Get(ctx, r.ID)
+	if err != nil {
+		t.Fatalf("failed to get: %v", err)
+	}
+	if got.Completed {
+		t.Fatal("expected the recurring reminder to stay open")
+	}
+	if want := due.AddDate(0, 0, 1); got.Due == nil || !got.Due.Equal(want) {
+		t.Errorf("expected due %v, got %v", want, got.Due)
+	}
+	if got.Recurrence == nil || got.Recurrence.String() != "FREQ=DAILY;COUNT=1" {
+		t.Errorf("expected one occurrence left, got %v", got.Recurrence)
+	}
+
+	// The last occurrence closes it.
+	if err := store.Complete(ctx, r.ID); err != nil {
+		t.Fatalf("failed to complete: %v", err)
+	}
+	if got, _ := store.Get(ctx, r.ID); !got.Completed {
+		t.Error("expected the reminder to close after its last occurrence")
+	}
+}
+
+func TestStore_Conformance(t *testing.T) {
+	// Subtasks cannot be read or written by scripts.
+	storetest.RunConformance(t, func(t *testing.T) protocol.Store {
+		return New("Recall", WithRunner(appletest.NewReminders()))
+	}, storetest.Skip("Subtasks"))
+}
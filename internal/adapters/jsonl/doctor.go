@@ -0,0 +1,192 @@
+package jsonl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var errMissingID = errors.New("record has no id")
+
+// LineError is a line of the file that could not be read.
+type LineError struct {
+	Line int    `json:"line" yaml:"line"`
+	Text string `json:"text" yaml:"text"`
+	Err  error  `json:"-" yaml:"-"`
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// MalformedError reports lines skipped because they could not be read,
+// often left behind by a merge that went wrong.
+type MalformedError struct {
+	Path  string
+	Lines []*LineError
+}
+
+func (e *MalformedError) Error() string {
+	lines := make([]string, len(e.Lines))
+	for i, l := range e.Lines {
+		lines[i] = strconv.Itoa(l.Line)
+	}
+	noun := "line"
+	if len(e.Lines) > 1 {
+		noun = "lines"
+	}
+	return fmt.Sprintf("%s: skipped %d unreadable %s (%s)", filepath.Base(e.Path), len(e.Lines), noun, strings.Join(lines, ", "))
+}
+
+// Kinds of Problem found by Check.
+const (
+	ProblemMalformed     = "malformed"
+	ProblemDuplicateID   = "duplicate_id"
+	ProblemPriority      = "invalid_priority"
+	ProblemMissingParent = "missing_parent"
+	ProblemTempFile      = "orphaned_tmp"
+)
+
+// Problem is something wrong with the file.
+type Problem struct {
+	Kind string `json:"kind" yaml:"kind"`
+
+	// Line is the line the problem is on, or 0 when it is not about one
+	// line.
+	Line    int    `json:"line,omitempty" yaml:"line,omitempty"`
+	ID      string `json:"id,omitempty" yaml:"id,omitempty"`
+	Message string `json:"message" yaml:"message"`
+}
+
+// Report is the result of Check.
+type Report struct {
+	// Lines is the number of non-blank lines in the file.
+	Lines int `json:"lines" yaml:"lines"`
+
+	// Reminders is the number of live reminders.
+	Reminders int `json:"reminders" yaml:"reminders"`
+
+	Problems []*Problem `json:"problems" yaml:"problems"`
+}
+
+// Check validates the file. It reports lines that cannot be read, IDs used
+// by two different reminders, priorities outside 0-3, subtasks whose
+// parent does not exist and a temporary file left by an interrupted
+// rewrite.
+func (s *Store) Check(ctx context.Context) (*Report, error) {
+	// Rewrites hold the exclusive lock, so a temporary file seen under
+	// the shared lock was left behind.
+	unlock, err := s.lock(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	report := &Report{Problems: []*Problem{}}
+	add := func(p *Problem) {
+		report.Problems = append(report.Problems, p)
+	}
+
+	created := map[string]time.Time{} // ID -> creation time of its reminder
+	duplicate := map[string]bool{}
+	lastLine := map[string]int{} // ID -> line of its latest version
+	err = s.scan(func(n int, line []byte, rec *record, err error) {
+		report.Lines++
+		switch {
+		case rec == nil:
+			add(&Problem{Kind: ProblemMalformed, Line: n, Message: err.Error()})
+		case rec.Deleted:
+			delete(created, rec.ID)
+		default:
+			lastLine[rec.ID] = n
+			// Versions of one reminder share its creation time; a
+			// different one means another reminder has the same ID.
+			first, ok := created[rec.ID]
+			if !ok {
+				created[rec.ID] = rec.CreatedAt
+			} else if !first.Equal(rec.CreatedAt) && !duplicate[rec.ID] {
+				duplicate[rec.ID] = true
+				add(&Problem{Kind: ProblemDuplicateID, Line: n, ID: rec.ID,
+					Message: fmt.Sprintf("%q reuses the ID of a reminder created %s", rec.Title, first.Format(time.RFC3339))})
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	live, err := s.readAll()
+	if err != nil {
+		return nil, err
+	}
+	report.Reminders = len(live)
+
+	ids := make(map[string]bool, len(live))
+	for _, r := range live {
+		ids[r.ID] = true
+	}
+	for _, r := range live {
+		if r.Priority < 0 || r.Priority > 3 {
+			add(&Problem{Kind: ProblemPriority, Line: lastLine[r.ID], ID: r.ID,
+				Message: fmt.Sprintf("%q has priority %d, outside 0-3", r.Title, r.Priority)})
+		}
+		if r.ParentID != "" && !ids[r.ParentID] {
+			add(&Problem{Kind: ProblemMissingParent, Line: lastLine[r.ID], ID: r.ID,
+				Message: fmt.Sprintf("%q is a subtask of %s, which does not exist", r.Title, r.ParentID)})
+		}
+	}
+
+	if _, err := os.Stat(s.path + ".tmp"); err == nil {
+		add(&Problem{Kind: ProblemTempFile,
+			Message: fmt.Sprintf("%s was left by an interrupted rewrite; check it and remove it", filepath.Base(s.path)+".tmp")})
+	}
+
+	return report, nil
+}
+
+// RejectsPath returns the file Quarantine moves unreadable lines to.
+func (s *Store) RejectsPath() string {
+	return s.path + ".rejects"
+}
+
+// Quarantine moves lines that cannot be read to the rejects file, leaving
+// every other line as it is, and returns how many were moved.
+func (s *Store) Quarantine(ctx context.Context) (int, error) {
+	unlock, err := s.lock(ctx, true)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	var good, bad [][]byte
+	err = s.scan(func(_ int, line []byte, rec *record, _ error) {
+		if rec == nil {
+			bad = append(bad, line)
+		} else {
+			good = append(good, line)
+		}
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(bad) == 0 {
+		return 0, nil
+	}
+
+	if err := appendLines(s.RejectsPath(), bad); err != nil {
+		return 0, fmt.Errorf("writing rejects: %w", err)
+	}
+	if err := s.writeAll(nil, good); err != nil {
+		return 0, err
+	}
+	return len(bad), nil
+}
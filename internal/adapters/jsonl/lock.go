@@ -0,0 +1,111 @@
+package jsonl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// DefaultLockTimeout is how long an operation waits for another process
+// to release the store before giving up.
+const DefaultLockTimeout = 10 * time.Second
+
+// lockPollInterval is how often a held lock is retried.
+const lockPollInterval = 10 * time.Millisecond
+
+// ErrLocked is returned when the store stays locked by another process
+// for longer than the lock timeout.
+var ErrLocked = errors.New("reminders file is locked by another process")
+
+// errWouldBlock is returned by tryLock when the lock is held elsewhere.
+var errWouldBlock = errors.New("lock is held")
+
+// lock takes the sidecar lock file next to the store, shared for reads
+// and exclusive for writes, so several rc processes can use the same
+// file safely, and then s.mu for goroutines of this one. The file lock
+// is skipped when the context comes from Locked. It waits up to the lock
+// timeout and returns a function that releases the lock.
+func (s *Store) lock(ctx context.Context, exclusive bool) (func(), error) {
+	release := func() {}
+	if ctx.Value(lockedKey{s}) == nil {
+		var err error
+		if release, err = s.lockFile(ctx, exclusive); err != nil {
+			return nil, err
+		}
+	}
+
+	if exclusive {
+		s.mu.Lock()
+		return func() {
+			s.mu.Unlock()
+			release()
+		}, nil
+	}
+	s.mu.RLock()
+	return func() {
+		s.mu.RUnlock()
+		release()
+	}, nil
+}
+
+// lockedKey marks a context whose caller holds the store's file lock
+// through Locked.
+type lockedKey struct{ s *Store }
+
+// Locked runs fn holding the store's exclusive file lock, so that a change
+// and whatever must follow it, such as committing the file to git, happen
+// as one step for other rc processes. Store operations given the context
+// passed to fn run under that lock rather than waiting for it.
+func (s *Store) Locked(ctx context.Context, fn func(ctx context.Context) error) error {
+	if ctx.Value(lockedKey{s}) != nil {
+		return fn(ctx)
+	}
+
+	release, err := s.lockFile(ctx, true)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	return fn(context.WithValue(ctx, lockedKey{s}, true))
+}
+
+// lockFile takes the sidecar lock file.
+func (s *Store) lockFile(ctx context.Context, exclusive bool) (func(), error) {
+	f, err := os.OpenFile(s.lockPath(), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("opening lock file: %w", err)
+	}
+
+	deadline := time.Now().Add(s.lockTimeout)
+	for {
+		err := tryLock(f, exclusive)
+		if err == nil {
+			return func() {
+				unlock(f)
+				f.Close()
+			}, nil
+		}
+		if !errors.Is(err, errWouldBlock) {
+			f.Close()
+			return nil, fmt.Errorf("locking %s: %w", s.lockPath(), err)
+		}
+		if time.Now().After(deadline) {
+			f.Close()
+			return nil, fmt.Errorf("%w: waited %s for %s", ErrLocked, s.lockTimeout, s.lockPath())
+		}
+
+		select {
+		case <-ctx.Done():
+			f.Close()
+			return nil, ctx.Err()
+		case <-time.After(lockPollInterval):
+		}
+	}
+}
+
+func (s *Store) lockPath() string {
+	return s.path + ".lock"
+}
@@ -0,0 +1,13 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package jsonl
+
+import "os"
+
+// Without flock there is no cross-process locking; the in-process mutex
+// still serializes goroutines sharing a Store.
+func tryLock(f *os.File, exclusive bool) error {
+	return nil
+}
+
+func unlock(f *os.File) {}
@@ -0,0 +1,235 @@
+package jsonl
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/shaneoxm/recall/internal/protocol"
+)
+
+// MergeResult describes what Merge did.
+type MergeResult struct {
+	// Reminders is the number of live reminders in the merged file.
+	Reminders int `json:"reminders" yaml:"reminders"`
+
+	// Conflicts is the number of fields both sides changed differently,
+	// settled in favour of the side updated last.
+	Conflicts int `json:"conflicts" yaml:"conflicts"`
+}
+
+// Merge is a three-way merge of reminders files for git: base is the
+// common ancestor, and ours and theirs are the two versions being merged.
+// The result is written to ours, in canonical form, as git expects of a
+// merge driver.
+//
+// Reminders are matched by ID. A field changed on one side only takes that
+// side's value; a field changed on both takes the value from the side with
+// the later UpdatedAt. Tags and links are merged as sets, so additions from
+// both sides are kept. Completion is sticky: a reminder completed on either
+// side stays completed. A reminder deleted on one side is dropped unless
+// the other side changed it. Unreadable lines from both sides are kept at
+// the end of the file.
+func Merge(base, ours, theirs string) (*MergeResult, error) {
+	b, err := readMergeSide(base)
+	if err != nil {
+		return nil, err
+	}
+	o, err := readMergeSide(ours)
+	if err != nil {
+		return nil, err
+	}
+	t, err := readMergeSide(theirs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &MergeResult{}
+	var merged []*protocol.Reminder
+	add := func(r *protocol.Reminder) {
+		if r != nil {
+			merged = append(merged, r)
+		}
+	}
+
+	// Ours keeps its order, and reminders only theirs has follow in
+	// theirs' order, so the merged file diffs cleanly against ours.
+	for _, r := range o.reminders {
+		add(result.mergeReminder(b.byID[r.ID], r, t.byID[r.ID]))
+	}
+	for _, r := range t.reminders {
+		if o.byID[r.ID] == nil {
+			add(result.mergeReminder(b.byID[r.ID], nil, r))
+		}
+	}
+	result.Reminders = len(merged)
+
+	malformed := o.malformed
+	for _, line := range t.malformed {
+		if !slices.ContainsFunc(malformed, func(l []byte) bool { return bytes.Equal(l, line) }) {
+			malformed = append(malformed, line)
+		}
+	}
+
+	s := &Store{path: ours}
+	if err := s.writeAll(merged, malformed); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// mergeSide is one version of the file being merged.
+type mergeSide struct {
+	reminders []*protocol.Reminder
+	byID      map[string]*protocol.Reminder
+	malformed [][]byte
+}
+
+func readMergeSide(path string) (*mergeSide, error) {
+	s := &Store{path: path}
+	side := &mergeSide{byID: map[string]*protocol.Reminder{}}
+
+	var err error
+	if side.reminders, err = s.readAll(); err != nil {
+		return nil, err
+	}
+	for _, r := range side.reminders {
+		side.byID[r.ID] = r
+	}
+
+	err = s.scan(func(_ int, line []byte, rec *record, _ error) {
+		if rec == nil {
+			side.malformed = append(side.malformed, line)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return side, nil
+}
+
+// mergeReminder merges one reminder. base, ours or theirs is nil when the
+// reminder does not exist on that side; the result is nil when it should
+// be deleted.
+func (m *MergeResult) mergeReminder(base, ours, theirs *protocol.Reminder) *protocol.Reminder {
+	switch {
+	case ours == nil && theirs == nil:
+		return nil
+	case ours == nil:
+		// Deleted on our side, or added on theirs.
+		if base != nil && same(base, theirs) {
+			return nil
+		}
+		return theirs
+	case theirs == nil:
+		if base != nil && same(base, ours) {
+			return nil
+		}
+		return ours
+	}
+
+	if base == nil {
+		// Added on both sides with the same ID.
+		base = &protocol.Reminder{}
+	}
+	oursLast := !theirs.UpdatedAt.After(ours.UpdatedAt)
+
+	r := &protocol.Reminder{
+		ID:            ours.ID,
+		Title:         pick(m, base.Title, ours.Title, theirs.Title, oursLast),
+		Due:           pick(m, base.Due, ours.Due, theirs.Due, oursLast),
+		Notes:         pick(m, base.Notes, ours.Notes, theirs.Notes, oursLast),
+		Links:         mergeSet(base.Links, ours.Links, theirs.Links),
+		Tags:          mergeSet(base.Tags, ours.Tags, theirs.Tags),
+		Priority:      pick(m, base.Priority, ours.Priority, theirs.Priority, oursLast),
+		Completed:     ours.Completed || theirs.Completed,
+		CreatedAt:     pick(m, base.CreatedAt, ours.CreatedAt, theirs.CreatedAt, oursLast),
+		UpdatedAt:     latest(ours.UpdatedAt, theirs.UpdatedAt),
+		ParentID:      pick(m, base.ParentID, ours.ParentID, theirs.ParentID, oursLast),
+		IsSubtask:     pick(m, base.IsSubtask, ours.IsSubtask, theirs.IsSubtask, oursLast),
+		Recurrence:    pick(m, base.Recurrence, ours.Recurrence, theirs.Recurrence, oursLast),
+		SnoozeCount:   max(ours.SnoozeCount, theirs.SnoozeCount),
+		LastSnoozedAt: pick(m, base.LastSnoozedAt, ours.LastSnoozedAt, theirs.LastSnoozedAt, oursLast),
+	}
+
+	// The reminder was completed when the first side completed it.
+	switch {
+	case ours.CompletedAt == nil:
+		r.CompletedAt = theirs.CompletedAt
+	case theirs.CompletedAt == nil || !theirs.CompletedAt.Before(*ours.CompletedAt):
+		r.CompletedAt = ours.CompletedAt
+	default:
+		r.CompletedAt = theirs.CompletedAt
+	}
+
+	keys := map[string]bool{}
+	for _, md := range []map[string]string{base.Metadata, ours.Metadata, theirs.Metadata} {
+		for k := range md {
+			keys[k] = true
+		}
+	}
+	for k := range keys {
+		bv, bok := base.Metadata[k]
+		ov, ook := ours.Metadata[k]
+		tv, tok := theirs.Metadata[k]
+		type value struct {
+			Value string
+			Set   bool
+		}
+		if v := pick(m, value{bv, bok}, value{ov, ook}, value{tv, tok}, oursLast); v.Set {
+			if r.Metadata == nil {
+				r.Metadata = map[string]string{}
+			}
+			r.Metadata[k] = v.Value
+		}
+	}
+
+	return r
+}
+
+// pick is a three-way merge of one field. When both sides changed it
+// differently, the side updated last wins and the conflict is counted.
+func pick[T any](m *MergeResult, base, ours, theirs T, oursLast bool) T {
+	switch {
+	case same(ours, theirs), same(theirs, base):
+		return ours
+	case same(ours, base):
+		return theirs
+	}
+	m.Conflicts++
+	if oursLast {
+		return ours
+	}
+	return theirs
+}
+
+// mergeSet is a three-way merge of a set: items either side added are
+// kept, and items either side removed are dropped. Ours' order comes
+// first.
+func mergeSet(base, ours, theirs []string) []string {
+	var merged []string
+	for _, v := range slices.Concat(ours, theirs) {
+		removed := slices.Contains(base, v) && (!slices.Contains(ours, v) || !slices.Contains(theirs, v))
+		if !removed && !slices.Contains(merged, v) {
+			merged = append(merged, v)
+		}
+	}
+	return merged
+}
+
+// same reports whether a and b are stored identically. Comparing the JSON
+// encoding, rather than with reflect.DeepEqual, treats times read from
+// separate files as equal when they are written the same way.
+func same(a, b any) bool {
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(ja, jb)
+}
+
+func latest(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
@@ -4,46 +4,84 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/shaneoxm/recall/internal/protocol"
 )
 
-var ErrNotFound = errors.New("reminder not found")
+var ErrNotFound = fmt.Errorf("reminder %w", protocol.ErrNotFound)
 
-// Store implements protocol.Store using JSONL file storage.
+// Store implements protocol.Store using JSONL file storage. Every
+// operation holds a lock on a sidecar ".lock" file, so several processes
+// can share one file.
 type Store struct {
-	path string
-	mu   sync.RWMutex
+	path        string
+	mu          sync.RWMutex
+	lockTimeout time.Duration
+	warn        func(error)
+}
+
+// Option configures a Store.
+type Option func(*Store)
+
+// WithLockTimeout sets how long operations wait for another process to
+// release the file before failing with ErrLocked.
+func WithLockTimeout(d time.Duration) Option {
+	return func(s *Store) {
+		s.lockTimeout = d
+	}
+}
+
+// WithWarnings sets fn to be called with problems that do not stop an
+// operation, such as a *MalformedError when List skips unreadable lines.
+func WithWarnings(fn func(error)) Option {
+	return func(s *Store) {
+		s.warn = fn
+	}
 }
 
 // New creates a new JSONL store at the given path.
-func New(path string) (*Store, error) {
+func New(path string, opts ...Option) (*Store, error) {
 	dir := filepath.Dir(path)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return nil, fmt.Errorf("creating directory: %w", err)
 	}
 
-	return &Store{path: path}, nil
+	s := &Store{path: path, lockTimeout: DefaultLockTimeout}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s, nil
+}
+
+// Path returns the path of the reminders file.
+func (s *Store) Path() string {
+	return s.path
 }
 
 // Add creates a new reminder.
 func (s *Store) Add(ctx context.Context, reminder *protocol.Reminder) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	unlock, err := s.lock(ctx, true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
 
-	return s.appendReminder(reminder)
+	return s.appendLine(reminder)
 }
 
 // Get retrieves a reminder by ID.
 func (s *Store) Get(ctx context.Context, id string) (*protocol.Reminder, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	unlock, err := s.lock(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
 
 	reminders, err := s.readAll()
 	if err != nil {
@@ -59,163 +97,294 @@ func (s *Store) Get(ctx context.Context, id string) (*protocol.Reminder, error)
 	return nil, ErrNotFound
 }
 
-// List returns all reminders matching the filter.
+// List returns all reminders matching the filter. Unreadable lines are
+// skipped and reported through the WithWarnings callback.
 func (s *Store) List(ctx context.Context, filter *protocol.ListFilter) ([]*protocol.Reminder, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	unlock, err := s.lock(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
 
-	reminders, err := s.readAll()
+	reminders, malformed, err := s.read()
 	if err != nil {
 		return nil, err
 	}
+	if len(malformed) > 0 && s.warn != nil {
+		s.warn(&MalformedError{Path: s.path, Lines: malformed})
+	}
 
 	if filter == nil {
 		return reminders, nil
 	}
 
-	var result []*protocol.Reminder
-	for _, r := range reminders {
-		if s.matchesFilter(r, filter) {
-			result = append(result, r)
-		}
-	}
-
-	return result, nil
+	return filter.Apply(reminders), nil
 }
 
-// Update modifies an existing reminder.
+// Update appends the new version of an existing reminder.
 func (s *Store) Update(ctx context.Context, reminder *protocol.Reminder) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	reminders, err := s.readAll()
+	unlock, err := s.lock(ctx, true)
 	if err != nil {
 		return err
 	}
+	defer unlock()
 
-	found := false
-	for i, r := range reminders {
-		if r.ID == reminder.ID {
-			reminders[i] = reminder
-			found = true
-			break
-		}
+	if _, err := s.find(reminder.ID); err != nil {
+		return err
 	}
+	return s.appendLine(reminder)
+}
 
-	if !found {
-		return ErrNotFound
+// Delete appends a tombstone for a reminder.
+func (s *Store) Delete(ctx context.Context, id string) error {
+	unlock, err := s.lock(ctx, true)
+	if err != nil {
+		return err
 	}
+	defer unlock()
 
-	return s.writeAll(reminders)
+	if _, err := s.find(id); err != nil {
+		return err
+	}
+	return s.appendLine(tombstone{ID: id, Deleted: true, DeletedAt: time.Now()})
 }
 
-// Delete removes a reminder by ID.
-func (s *Store) Delete(ctx context.Context, id string) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+// Complete appends the completed version of a reminder.
+func (s *Store) Complete(ctx context.Context, id string) error {
+	unlock, err := s.lock(ctx, true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
 
-	reminders, err := s.readAll()
+	r, err := s.find(id)
 	if err != nil {
 		return err
 	}
+	r.Complete()
+	return s.appendLine(r)
+}
 
-	newReminders := make([]*protocol.Reminder, 0, len(reminders))
-	found := false
-	for _, r := range reminders {
-		if r.ID == id {
-			found = true
-			continue
+// CompactResult describes what Compact did.
+type CompactResult struct {
+	// Lines is the number of lines before compacting.
+	Lines int `json:"lines" yaml:"lines"`
+
+	// Live is the number of reminders kept, one line each.
+	Live int `json:"live" yaml:"live"`
+
+	// Archived is the number of superseded versions and tombstones
+	// written to the archive, if one was given.
+	Archived int `json:"archived" yaml:"archived"`
+
+	// Malformed is the number of unreadable lines, which are kept at the
+	// end of the file untouched.
+	Malformed int `json:"malformed,omitempty" yaml:"malformed,omitempty"`
+}
+
+// Compact rewrites the file with one line per live reminder, dropping
+// superseded versions, deleted reminders and their tombstones. When
+// archivePath is set, the dropped lines are appended there first. Lines
+// that cannot be read are kept as they are.
+func (s *Store) Compact(ctx context.Context, archivePath string) (*CompactResult, error) {
+	unlock, err := s.lock(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	type entry struct {
+		line []byte
+		rec  *record
+	}
+	var entries []entry
+	var malformed [][]byte
+	latest := map[string]int{} // ID -> index of its last line
+	err = s.scan(func(_ int, line []byte, rec *record, _ error) {
+		if rec == nil {
+			malformed = append(malformed, line)
+			return
+		}
+		latest[rec.ID] = len(entries)
+		entries = append(entries, entry{line, rec})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var dropped [][]byte
+	for i, e := range entries {
+		if latest[e.rec.ID] != i || e.rec.Deleted {
+			dropped = append(dropped, e.line)
 		}
-		newReminders = append(newReminders, r)
 	}
 
-	if !found {
-		return ErrNotFound
+	// Reminders keep the position they were first added at, so
+	// compacting changes as few lines as possible in a git diff.
+	live, err := s.readAll()
+	if err != nil {
+		return nil, err
+	}
+	result := &CompactResult{Lines: len(entries) + len(malformed), Live: len(live), Malformed: len(malformed)}
+
+	if archivePath != "" && len(dropped) > 0 {
+		if err := appendLines(archivePath, dropped); err != nil {
+			return nil, fmt.Errorf("archiving: %w", err)
+		}
+		result.Archived = len(dropped)
 	}
 
-	return s.writeAll(newReminders)
+	if err := s.writeAll(live, malformed); err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 
-// Complete marks a reminder as completed.
-func (s *Store) Complete(ctx context.Context, id string) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+// record is one line of the file: a version of a reminder, or a
+// tombstone when Deleted is set.
+type record struct {
+	protocol.Reminder
+	Deleted bool `json:"deleted,omitempty"`
+}
 
+// tombstone marks a reminder as deleted.
+type tombstone struct {
+	ID        string    `json:"id"`
+	Deleted   bool      `json:"deleted"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// find returns the current version of a reminder.
+func (s *Store) find(id string) (*protocol.Reminder, error) {
 	reminders, err := s.readAll()
 	if err != nil {
-		return err
+		return nil, err
 	}
-
 	for _, r := range reminders {
 		if r.ID == id {
-			r.Complete()
-			return s.writeAll(reminders)
+			return r, nil
 		}
 	}
-
-	return ErrNotFound
+	return nil, ErrNotFound
 }
 
-func (s *Store) appendReminder(reminder *protocol.Reminder) error {
-	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+func (s *Store) appendLine(v any) error {
+	data, err := json.Marshal(v)
 	if err != nil {
-		return fmt.Errorf("opening file: %w", err)
+		return fmt.Errorf("marshaling reminder: %w", err)
 	}
-	defer f.Close()
+	if err := appendLines(s.path, [][]byte{data}); err != nil {
+		return fmt.Errorf("writing reminder: %w", err)
+	}
+	return nil
+}
 
-	data, err := json.Marshal(reminder)
+func appendLines(path string, lines [][]byte) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 	if err != nil {
-		return fmt.Errorf("marshaling reminder: %w", err)
+		return fmt.Errorf("opening file: %w", err)
 	}
 
-	if _, err := f.Write(append(data, '\n')); err != nil {
-		return fmt.Errorf("writing reminder: %w", err)
+	var buf []byte
+	for _, line := range lines {
+		buf = append(append(buf, line...), '\n')
 	}
-
-	return nil
+	if _, err := f.Write(buf); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
 }
 
-func (s *Store) readAll() ([]*protocol.Reminder, error) {
+// scan calls fn for each non-blank line of the file, in order, with its
+// line number. For lines that are not a valid record, rec is nil and err
+// says why.
+func (s *Store) scan(fn func(n int, line []byte, rec *record, err error)) error {
 	f, err := os.Open(s.path)
 	if os.IsNotExist(err) {
-		return nil, nil
+		return nil
 	}
 	if err != nil {
-		return nil, fmt.Errorf("opening file: %w", err)
+		return fmt.Errorf("opening file: %w", err)
 	}
 	defer f.Close()
 
-	var reminders []*protocol.Reminder
-	seen := make(map[string]*protocol.Reminder)
-
 	scanner := bufio.NewScanner(f)
-	for scanner.Scan() {
+	for n := 1; scanner.Scan(); n++ {
 		line := strings.TrimSpace(scanner.Text())
 		if line == "" {
 			continue
 		}
 
-		var r protocol.Reminder
-		if err := json.Unmarshal([]byte(line), &r); err != nil {
-			continue // Skip malformed lines
+		var rec record
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			fn(n, []byte(line), nil, err)
+			continue
 		}
-
-		// Latest version wins (append-only semantics)
-		seen[r.ID] = &r
+		if rec.ID == "" {
+			fn(n, []byte(line), nil, errMissingID)
+			continue
+		}
+		fn(n, []byte(line), &rec, nil)
 	}
 
 	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("reading file: %w", err)
+		return fmt.Errorf("reading file: %w", err)
 	}
+	return nil
+}
 
-	for _, r := range seen {
-		reminders = append(reminders, r)
+// readAll returns the current version of every live reminder, in the
+// order they were first added. The file is a log: the latest line for an
+// ID wins, and a tombstone removes it.
+func (s *Store) readAll() ([]*protocol.Reminder, error) {
+	reminders, _, err := s.read()
+	return reminders, err
+}
+
+// read is readAll, also returning the lines it skipped because they could
+// not be read.
+func (s *Store) read() ([]*protocol.Reminder, []*LineError, error) {
+	var order []string
+	pos := make(map[string]int) // ID -> its slot in order
+	latest := make(map[string]*protocol.Reminder)
+	var malformed []*LineError
+
+	err := s.scan(func(n int, line []byte, rec *record, err error) {
+		if rec == nil {
+			malformed = append(malformed, &LineError{Line: n, Text: string(line), Err: err})
+			return
+		}
+		if rec.Deleted {
+			delete(pos, rec.ID)
+			delete(latest, rec.ID)
+			return
+		}
+		if _, ok := pos[rec.ID]; !ok {
+			pos[rec.ID] = len(order)
+			order = append(order, rec.ID)
+		}
+		r := rec.Reminder
+		latest[r.ID] = &r
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var reminders []*protocol.Reminder
+	for i, id := range order {
+		if p, ok := pos[id]; ok && p == i {
+			reminders = append(reminders, latest[id])
+		}
 	}
 
-	return reminders, nil
+	return reminders, malformed, nil
 }
 
-func (s *Store) writeAll(reminders []*protocol.Reminder) error {
+// writeAll replaces the file with reminders followed by raw lines.
+// Reminders are written in canonical form: fields in struct order and
+// metadata keys sorted, as encoding/json does.
+func (s *Store) writeAll(reminders []*protocol.Reminder, raw [][]byte) error {
 	tmpPath := s.path + ".tmp"
 	f, err := os.Create(tmpPath)
 	if err != nil {
@@ -235,6 +404,13 @@ func (s *Store) writeAll(reminders []*protocol.Reminder) error {
 			return fmt.Errorf("writing reminder: %w", err)
 		}
 	}
+	for _, line := range raw {
+		if _, err := f.Write(append(line, '\n')); err != nil {
+			f.Close()
+			os.Remove(tmpPath)
+			return fmt.Errorf("writing reminder: %w", err)
+		}
+	}
 
 	if err := f.Close(); err != nil {
 		os.Remove(tmpPath)
@@ -248,52 +424,3 @@ func (s *Store) writeAll(reminders []*protocol.Reminder) error {
 
 	return nil
 }
-
-func (s *Store) matchesFilter(r *protocol.Reminder, filter *protocol.ListFilter) bool {
-	// Filter completed
-	if !filter.IncludeCompleted && r.Completed {
-		return false
-	}
-
-	// Filter by tags
-	if len(filter.Tags) > 0 {
-		hasTag := false
-		for _, filterTag := range filter.Tags {
-			for _, reminderTag := range r.Tags {
-				if strings.EqualFold(reminderTag, filterTag) {
-					hasTag = true
-					break
-				}
-			}
-			if hasTag {
-				break
-			}
-		}
-		if !hasTag {
-			return false
-		}
-	}
-
-	// Filter by due date
-	if filter.DueBefore != nil && r.Due != nil {
-		if r.Due.After(*filter.DueBefore) {
-			return false
-		}
-	}
-	if filter.DueAfter != nil && r.Due != nil {
-		if r.Due.Before(*filter.DueAfter) {
-			return false
-		}
-	}
-
-	// Filter by search text
-	if filter.Search != "" {
-		search := strings.ToLower(filter.Search)
-		if !strings.Contains(strings.ToLower(r.Title), search) &&
-			!strings.Contains(strings.ToLower(r.Notes), search) {
-			return false
-		}
-	}
-
-	return true
-}
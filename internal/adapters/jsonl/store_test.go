@@ -2,12 +2,20 @@ package jsonl
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/shaneoxm/recall/internal/protocol"
+	"github.com/shaneoxm/recall/internal/protocol/storetest"
 )
 
 func TestStore_AddAndGet(t *testing.T) {
@@ -280,3 +288,614 @@ func TestStore_CreatesDirectory(t *testing.T) {
 		t.Error("expected directory to be created")
 	}
 }
+
+func TestStore_Conformance(t *testing.T) {
+	storetest.RunConformance(t, func(t *testing.T) protocol.Store {
+		store, err := New(filepath.Join(t.TempDir(), "reminders.jsonl"))
+		if err != nil {
+			t.Fatalf("failed to create store: %v", err)
+		}
+		return store
+	})
+}
+
+// addAndComplete adds n reminders through its own Store, completing every
+// other one, as a separate rc process would.
+func addAndComplete(path, prefix string, n int) error {
+	store, err := New(path, WithLockTimeout(time.Minute))
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	for i := range n {
+		r := protocol.NewReminder(fmt.Sprintf("%s-%d", prefix, i))
+		if err := store.Add(ctx, r); err != nil {
+			return err
+		}
+		if i%2 == 0 {
+			if err := store.Complete(ctx, r.ID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func expectCounts(t *testing.T, path string, total, completed int) {
+	t.Helper()
+	store, err := New(path)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	all, err := store.List(context.Background(), &protocol.ListFilter{IncludeCompleted: true})
+	if err != nil {
+		t.Fatalf("failed to list: %v", err)
+	}
+
+	done := 0
+	for _, r := range all {
+		if r.Completed {
+			done++
+		}
+	}
+	if len(all) != total || done != completed {
+		t.Errorf("expected %d reminders with %d completed, got %d with %d completed", total, completed, len(all), done)
+	}
+}
+
+func TestStore_ConcurrentStores(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "reminders.jsonl")
+	const workers, each = 8, 20
+
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- addAndComplete(path, "worker"+strconv.Itoa(w), each)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("worker failed: %v", err)
+		}
+	}
+
+	expectCounts(t, path, workers*each, workers*each/2)
+}
+
+func TestStore_ConcurrentProcesses(t *testing.T) {
+	if testing.Short() {
+		t.Skip("spawns processes")
+	}
+	path := filepath.Join(t.TempDir(), "reminders.jsonl")
+	const procs, each = 6, 15
+
+	cmds := make([]*exec.Cmd, procs)
+	for i := range cmds {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestHelperProcess$")
+		cmd.Env = append(os.Environ(),
+			"RECALL_JSONL_HELPER_PATH="+path,
+			"RECALL_JSONL_HELPER_PREFIX=proc"+strconv.Itoa(i),
+			"RECALL_JSONL_HELPER_COUNT="+strconv.Itoa(each),
+		)
+		if err := cmd.Start(); err != nil {
+			t.Fatalf("failed to start helper: %v", err)
+		}
+		cmds[i] = cmd
+	}
+	for _, cmd := range cmds {
+		if err := cmd.Wait(); err != nil {
+			t.Fatalf("helper failed: %v", err)
+		}
+	}
+
+	expectCounts(t, path, procs*each, procs*((each+1)/2))
+}
+
+// TestHelperProcess is run by TestStore_ConcurrentProcesses in child
+// processes.
+func TestHelperProcess(t *testing.T) {
+	path := os.Getenv("RECALL_JSONL_HELPER_PATH")
+	if path == "" {
+		t.Skip("only run as a helper process")
+	}
+	n, _ := strconv.Atoi(os.Getenv("RECALL_JSONL_HELPER_COUNT"))
+	if err := addAndComplete(path, os.Getenv("RECALL_JSONL_HELPER_PREFIX"), n); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStore_LockTimeout(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "reminders.jsonl")
+	holder, _ := New(path)
+	store, _ := New(path, WithLockTimeout(50*time.Millisecond))
+	ctx := context.Background()
+
+	unlock, err := holder.lock(ctx, true)
+	if err != nil {
+		t.Fatalf("failed to take lock: %v", err)
+	}
+
+	start := time.Now()
+	err = store.Add(ctx, protocol.NewReminder("Blocked"))
+	if !errors.Is(err, ErrLocked) {
+		t.Fatalf("expected ErrLocked, got %v", err)
+	}
+	if waited := time.Since(start); waited < 50*time.Millisecond {
+		t.Errorf("expected to wait for the timeout, gave up after %s", waited)
+	}
+
+	unlock()
+	if err := store.Add(ctx, protocol.NewReminder("Unblocked")); err != nil {
+		t.Errorf("expected add to succeed once the lock is released, got %v", err)
+	}
+}
+
+func TestStore_SharedReadLocks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "reminders.jsonl")
+	reader, _ := New(path)
+	store, _ := New(path, WithLockTimeout(50*time.Millisecond))
+	ctx := context.Background()
+
+	unlock, err := reader.lock(ctx, false)
+	if err != nil {
+		t.Fatalf("failed to take lock: %v", err)
+	}
+	defer unlock()
+
+	if _, err := store.List(ctx, nil); err != nil {
+		t.Errorf("expected reads to share the lock, got %v", err)
+	}
+	if err := store.Add(ctx, protocol.NewReminder("Blocked")); !errors.Is(err, ErrLocked) {
+		t.Errorf("expected writes to wait for readers, got %v", err)
+	}
+}
+
+func TestStore_Locked(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "reminders.jsonl")
+	holder, _ := New(path)
+	other, _ := New(path, WithLockTimeout(50*time.Millisecond))
+	ctx := context.Background()
+
+	r := protocol.NewReminder("Inside")
+	err := holder.Locked(ctx, func(ctx context.Context) error {
+		if err := holder.Add(ctx, r); err != nil {
+			return err
+		}
+		// Nested calls reuse the lock.
+		if err := holder.Locked(ctx, func(ctx context.Context) error {
+			return holder.Complete(ctx, r.ID)
+		}); err != nil {
+			return err
+		}
+		if _, err := other.List(ctx, nil); !errors.Is(err, ErrLocked) {
+			t.Errorf("expected other stores to wait for the lock, got %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Locked failed: %v", err)
+	}
+
+	got, err := other.Get(ctx, r.ID)
+	if err != nil || !got.Completed {
+		t.Errorf("expected the completed reminder once unlocked, got %+v, %v", got, err)
+	}
+}
+
+func readLines(t *testing.T, path string) []string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", path, err)
+	}
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+}
+
+func TestStore_AppendOnly(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "reminders.jsonl")
+	store, _ := New(path)
+	ctx := context.Background()
+
+	keep := protocol.NewReminder("Keep")
+	drop := protocol.NewReminder("Drop")
+	store.Add(ctx, keep)
+	store.Add(ctx, drop)
+	before := readLines(t, path)
+
+	keep.SetNotes("edited")
+	if err := store.Update(ctx, keep); err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+	if err := store.Complete(ctx, keep.ID); err != nil {
+		t.Fatalf("failed to complete: %v", err)
+	}
+	if err := store.Delete(ctx, drop.ID); err != nil {
+		t.Fatalf("failed to delete: %v", err)
+	}
+
+	lines := readLines(t, path)
+	if len(lines) != 5 || !slices.Equal(lines[:2], before) {
+		t.Fatalf("expected 3 lines appended after the original 2, got:\n%s", strings.Join(lines, "\n"))
+	}
+	if !strings.Contains(lines[4], `"deleted":true`) {
+		t.Errorf("expected a tombstone, got %s", lines[4])
+	}
+
+	got, err := store.Get(ctx, keep.ID)
+	if err != nil || got.Notes != "edited" || !got.Completed {
+		t.Errorf("expected the latest version to win, got %+v, %v", got, err)
+	}
+	if _, err := store.Get(ctx, drop.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected deleted reminder to be gone, got %v", err)
+	}
+	if err := store.Delete(ctx, drop.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected deleting twice to fail, got %v", err)
+	}
+}
+
+func TestStore_Compact(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "reminders.jsonl")
+	archive := filepath.Join(dir, "reminders.archive.jsonl")
+	store, _ := New(path)
+	ctx := context.Background()
+
+	keep := protocol.NewReminder("Keep")
+	drop := protocol.NewReminder("Drop")
+	store.Add(ctx, keep)
+	store.Add(ctx, drop)
+	store.Complete(ctx, keep.ID)
+	store.Delete(ctx, drop.ID)
+
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	f.WriteString("{not json\n")
+	f.Close()
+
+	result, err := store.Compact(ctx, archive)
+	if err != nil {
+		t.Fatalf("failed to compact: %v", err)
+	}
+	want := CompactResult{Lines: 5, Live: 1, Archived: 3, Malformed: 1}
+	if *result != want {
+		t.Errorf("expected %+v, got %+v", want, *result)
+	}
+
+	lines := readLines(t, path)
+	if len(lines) != 2 || lines[1] != "{not json" {
+		t.Fatalf("expected one reminder and the malformed line, got:\n%s", strings.Join(lines, "\n"))
+	}
+	if got, err := store.Get(ctx, keep.ID); err != nil || !got.Completed {
+		t.Errorf("expected the completed version to be kept, got %+v, %v", got, err)
+	}
+	if archived := readLines(t, archive); len(archived) != 3 {
+		t.Errorf("expected 3 archived lines, got %d", len(archived))
+	}
+
+	// Compacting again has nothing to archive.
+	result, err = store.Compact(ctx, archive)
+	if err != nil || result.Archived != 0 || result.Live != 1 {
+		t.Errorf("expected a no-op compaction, got %+v, %v", result, err)
+	}
+}
+
+func TestStore_ListOrder(t *testing.T) {
+	store, _ := New(filepath.Join(t.TempDir(), "reminders.jsonl"))
+	ctx := context.Background()
+
+	var want []string
+	var added []*protocol.Reminder
+	for i := range 20 {
+		r := protocol.NewReminder(fmt.Sprintf("Reminder %02d", i))
+		store.Add(ctx, r)
+		added = append(added, r)
+		want = append(want, r.Title)
+	}
+
+	// Edits keep a reminder in place.
+	added[5].SetNotes("edited")
+	store.Update(ctx, added[5])
+	store.Complete(ctx, added[10].ID)
+
+	// A deleted and re-added reminder moves to the end.
+	store.Delete(ctx, added[0].ID)
+	store.Add(ctx, added[0])
+	want = append(want[1:], want[0])
+
+	for range 3 {
+		reminders, err := store.List(ctx, &protocol.ListFilter{IncludeCompleted: true})
+		if err != nil {
+			t.Fatalf("failed to list: %v", err)
+		}
+		var got []string
+		for _, r := range reminders {
+			got = append(got, r.Title)
+		}
+		if !slices.Equal(got, want) {
+			t.Fatalf("expected insertion order %v, got %v", want, got)
+		}
+	}
+}
+
+func TestStore_CompactStableDiff(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "reminders.jsonl")
+	store, _ := New(path)
+	ctx := context.Background()
+
+	var ids []string
+	for i := range 10 {
+		r := protocol.NewReminder(fmt.Sprintf("Reminder %d", i))
+		r.Metadata = map[string]string{"zeta": "1", "alpha": "2", "mid": "3"}
+		store.Add(ctx, r)
+		ids = append(ids, r.ID)
+	}
+	store.Compact(ctx, "")
+	before := readLines(t, path)
+
+	// Compacting an already compact file changes nothing.
+	store.Compact(ctx, "")
+	if again := readLines(t, path); !slices.Equal(again, before) {
+		t.Fatalf("expected compaction to be idempotent")
+	}
+
+	store.Complete(ctx, ids[4])
+	store.Compact(ctx, "")
+	after := readLines(t, path)
+
+	if len(after) != len(before) {
+		t.Fatalf("expected %d lines, got %d", len(before), len(after))
+	}
+	var changed []int
+	for i := range before {
+		if before[i] != after[i] {
+			changed = append(changed, i)
+		}
+	}
+	if !slices.Equal(changed, []int{4}) {
+		t.Errorf("expected only line 4 to change, got %v", changed)
+	}
+	if !strings.Contains(after[0], `"metadata":{"alpha":"2","mid":"3","zeta":"1"}`) {
+		t.Errorf("expected sorted metadata keys, got %s", after[0])
+	}
+}
+
+func writeLines(t *testing.T, path string, lines ...string) {
+	t.Helper()
+	data := strings.Join(lines, "\n") + "\n"
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	const (
+		t0 = `"created_at":"2026-01-01T09:00:00Z"`
+		t1 = `"updated_at":"2026-01-01T10:00:00Z"`
+		t2 = `"updated_at":"2026-01-01T11:00:00Z"`
+		t3 = `"updated_at":"2026-01-01T12:00:00Z"`
+	)
+	line := func(fields ...string) string {
+		return "{" + strings.Join(append(fields, t0), ",") + "}"
+	}
+
+	tests := []struct {
+		name      string
+		base      []string
+		ours      []string
+		theirs    []string
+		want      []string // title:completed:tags of each reminder, in order
+		conflicts int
+	}{
+		{
+			name:   "different fields",
+			base:   []string{line(`"id":"a","title":"Call","priority":1`, t1)},
+			ours:   []string{line(`"id":"a","title":"Call mom","priority":1`, t2)},
+			theirs: []string{line(`"id":"a","title":"Call","priority":3`, t3)},
+			want:   []string{"Call mom:false:"},
+		},
+		{
+			name:      "same field, later update wins",
+			base:      []string{line(`"id":"a","title":"Call"`, t1)},
+			ours:      []string{line(`"id":"a","title":"Call mom"`, t3)},
+			theirs:    []string{line(`"id":"a","title":"Call dad"`, t2)},
+			want:      []string{"Call mom:false:"},
+			conflicts: 1,
+		},
+		{
+			name:   "tags merged as sets",
+			base:   []string{line(`"id":"a","title":"Call","tags":["home","phone"]`, t1)},
+			ours:   []string{line(`"id":"a","title":"Call","tags":["home"]`, t2)},
+			theirs: []string{line(`"id":"a","title":"Call","tags":["home","phone","family"]`, t3)},
+			want:   []string{"Call:false:home,family"},
+		},
+		{
+			name:   "completion is sticky",
+			base:   []string{line(`"id":"a","title":"Call","completed":false`, t1)},
+			ours:   []string{line(`"id":"a","title":"Call","completed":true`, t2)},
+			theirs: []string{line(`"id":"a","title":"Call mom","completed":false`, t3)},
+			want:   []string{"Call mom:true:"},
+		},
+		{
+			name: "deleted and unchanged",
+			base: []string{line(`"id":"a","title":"Call"`, t1), line(`"id":"b","title":"Pay"`, t1)},
+			ours: []string{
+				line(`"id":"a","title":"Call"`, t1), line(`"id":"b","title":"Pay"`, t1),
+				`{"id":"a","deleted":true,"deleted_at":"2026-01-01T11:00:00Z"}`,
+			},
+			theirs: []string{line(`"id":"a","title":"Call"`, t1), line(`"id":"b","title":"Pay rent"`, t2)},
+			want:   []string{"Pay rent:false:"},
+		},
+		{
+			name:   "deleted and changed",
+			base:   []string{line(`"id":"a","title":"Call"`, t1)},
+			ours:   []string{line(`"id":"a","title":"Call mom"`, t2)},
+			theirs: []string{},
+			want:   []string{"Call mom:false:"},
+		},
+		{
+			name:   "added on both sides",
+			base:   []string{line(`"id":"a","title":"Call"`, t1)},
+			ours:   []string{line(`"id":"a","title":"Call"`, t1), line(`"id":"b","title":"Pay"`, t2)},
+			theirs: []string{line(`"id":"a","title":"Call"`, t1), line(`"id":"c","title":"Shop"`, t2)},
+			want:   []string{"Call:false:", "Pay:false:", "Shop:false:"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			base, ours, theirs := filepath.Join(dir, "base"), filepath.Join(dir, "ours"), filepath.Join(dir, "theirs")
+			writeLines(t, base, tt.base...)
+			writeLines(t, ours, tt.ours...)
+			writeLines(t, theirs, tt.theirs...)
+
+			result, err := Merge(base, ours, theirs)
+			if err != nil {
+				t.Fatalf("Merge failed: %v", err)
+			}
+			if result.Conflicts != tt.conflicts {
+				t.Errorf("expected %d conflicts, got %d", tt.conflicts, result.Conflicts)
+			}
+
+			store, _ := New(ours)
+			reminders, _ := store.List(context.Background(), &protocol.ListFilter{IncludeCompleted: true})
+			var got []string
+			for _, r := range reminders {
+				got = append(got, fmt.Sprintf("%s:%t:%s", r.Title, r.Completed, strings.Join(r.Tags, ",")))
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+			if lines := readLines(t, ours); len(lines) != len(tt.want) {
+				t.Errorf("expected one line per reminder, got %d lines", len(lines))
+			}
+		})
+	}
+}
+
+func TestMerge_KeepsMalformedLines(t *testing.T) {
+	dir := t.TempDir()
+	base, ours, theirs := filepath.Join(dir, "base"), filepath.Join(dir, "ours"), filepath.Join(dir, "theirs")
+	valid := `{"id":"a","title":"Call","completed":false,"created_at":"2026-01-01T09:00:00Z","updated_at":"2026-01-01T09:00:00Z"}`
+	writeLines(t, base, valid)
+	writeLines(t, ours, valid, "not json")
+	writeLines(t, theirs, valid, "not json", "{broken")
+
+	if _, err := Merge(base, ours, theirs); err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+
+	want := []string{valid, "not json", "{broken"}
+	if got := readLines(t, ours); !slices.Equal(got, want) {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestStore_ListWarnsMalformed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "reminders.jsonl")
+	writeLines(t, path,
+		`{"id":"a","title":"Call","completed":false,"created_at":"2026-01-01T09:00:00Z","updated_at":"2026-01-01T09:00:00Z"}`,
+		`<<<<<<< HEAD`,
+		``,
+		`{"title":"No ID"}`,
+	)
+
+	var warnings []error
+	store, _ := New(path, WithWarnings(func(err error) { warnings = append(warnings, err) }))
+	reminders, err := store.List(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(reminders) != 1 {
+		t.Errorf("expected 1 reminder, got %d", len(reminders))
+	}
+
+	if len(warnings) != 1 {
+		t.Fatalf("expected 1 warning, got %v", warnings)
+	}
+	var malformed *MalformedError
+	if !errors.As(warnings[0], &malformed) {
+		t.Fatalf("expected a MalformedError, got %T", warnings[0])
+	}
+	if got := warnings[0].Error(); got != "reminders.jsonl: skipped 2 unreadable lines (2, 4)" {
+		t.Errorf("unexpected warning: %s", got)
+	}
+	if !errors.Is(malformed.Lines[1], errMissingID) {
+		t.Errorf("expected line 4 to be missing its ID, got %v", malformed.Lines[1])
+	}
+}
+
+func TestStore_Check(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "reminders.jsonl")
+	const created = `"created_at":"2026-01-01T09:00:00Z","updated_at":"2026-01-01T09:00:00Z"`
+	writeLines(t, path,
+		`{"id":"a","title":"Call",`+created+`}`,
+		`{"id":"a","title":"Call mom",`+created+`}`,
+		`{"id":"a","title":"Pay rent","created_at":"2026-02-01T09:00:00Z","updated_at":"2026-02-01T09:00:00Z"}`,
+		`{"id":"b","title":"Urgent","priority":7,`+created+`}`,
+		`{"id":"c","title":"Step 1","parent_id":"gone","is_subtask":true,`+created+`}`,
+		`{"id":"d","title":"Old",`+created+`}`,
+		`{"id":"d","deleted":true,"deleted_at":"2026-01-02T09:00:00Z"}`,
+		`{"id":"d","title":"New","created_at":"2026-03-01T09:00:00Z","updated_at":"2026-03-01T09:00:00Z"}`,
+		`not json`,
+	)
+	os.WriteFile(path+".tmp", nil, 0644)
+
+	store, _ := New(path)
+	report, err := store.Check(context.Background())
+	if err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	if report.Lines != 9 || report.Reminders != 4 {
+		t.Errorf("expected 9 lines and 4 reminders, got %d and %d", report.Lines, report.Reminders)
+	}
+
+	var got []string
+	for _, p := range report.Problems {
+		got = append(got, fmt.Sprintf("%s:%d:%s", p.Kind, p.Line, p.ID))
+	}
+	want := []string{
+		"duplicate_id:3:a",
+		"malformed:9:",
+		"invalid_priority:4:b",
+		"missing_parent:5:c",
+		"orphaned_tmp:0:",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("expected problems %v, got %v", want, got)
+	}
+}
+
+func TestStore_Quarantine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "reminders.jsonl")
+	valid := []string{
+		`{"id":"a","title":"Call","completed":false,"created_at":"2026-01-01T09:00:00Z","updated_at":"2026-01-01T09:00:00Z"}`,
+		`{"id":"a","title":"Call mom","completed":false,"created_at":"2026-01-01T09:00:00Z","updated_at":"2026-01-01T10:00:00Z"}`,
+	}
+	writeLines(t, path, valid[0], "=======", valid[1], ">>>>>>> theirs")
+
+	store, _ := New(path)
+	moved, err := store.Quarantine(context.Background())
+	if err != nil {
+		t.Fatalf("Quarantine failed: %v", err)
+	}
+	if moved != 2 {
+		t.Errorf("expected 2 lines moved, got %d", moved)
+	}
+
+	// Valid lines are kept as they are, superseded versions included.
+	if got := readLines(t, path); !slices.Equal(got, valid) {
+		t.Errorf("expected %q, got %q", valid, got)
+	}
+	if got := readLines(t, store.RejectsPath()); !slices.Equal(got, []string{"=======", ">>>>>>> theirs"}) {
+		t.Errorf("unexpected rejects: %q", got)
+	}
+
+	if moved, _ := store.Quarantine(context.Background()); moved != 0 {
+		t.Errorf("expected nothing to move the second time, got %d", moved)
+	}
+}
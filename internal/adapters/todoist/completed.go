@@ -0,0 +1,92 @@
+package todoist
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/shaneoxm/recall/internal/protocol"
+)
+
+// completedSpan is the longest since/until range requested at once; the
+// API rejects ranges over three months.
+const completedSpan = 12 * 7 * 24 * time.Hour
+
+// todoistCompletedResponse is one page of the completed-tasks endpoint.
+type todoistCompletedResponse struct {
+	Items      []todoistTask `json:"items"`
+	NextCursor string        `json:"next_cursor"`
+}
+
+// listCompleted calls fn for each task completed within the completed
+// window, newest range first. GET /tasks only returns active tasks, so
+// completed ones come from /tasks/completed/by_completion_date.
+func (s *Store) listCompleted(ctx context.Context, scope url.Values, filter *protocol.ListFilter, fn func(*protocol.Reminder) error) error {
+	// Ranges are sent in whole seconds; round up so tasks completed in
+	// the current second are included.
+	until := s.now().UTC().Truncate(time.Second).Add(time.Second)
+	since := until.Add(-s.completedWindow)
+
+	for end := until; end.After(since); end = end.Add(-completedSpan) {
+		start := end.Add(-completedSpan)
+		if start.Before(since) {
+			start = since
+		}
+		if err := s.listCompletedRange(ctx, scope, start, end, filter, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Store) listCompletedRange(ctx context.Context, scope url.Values, since, until time.Time, filter *protocol.ListFilter, fn func(*protocol.Reminder) error) error {
+	cursor := ""
+	for page := 0; ; page++ {
+		if page == s.maxPages {
+			return fmt.Errorf("listing completed tasks: stopped after %d pages", s.maxPages)
+		}
+
+		query := url.Values{
+			"since": {since.Format(time.RFC3339)},
+			"until": {until.Format(time.RFC3339)},
+			"limit": {strconv.Itoa(s.pageSize)},
+		}
+		for _, k := range []string{"project_id", "section_id"} {
+			if v := scope.Get(k); v != "" {
+				query.Set(k, v)
+			}
+		}
+		if cursor != "" {
+			query.Set("cursor", cursor)
+		}
+
+		data, err := s.doRequest(ctx, "GET", "/tasks/completed/by_completion_date?"+query.Encode(), nil)
+		if err != nil {
+			return err
+		}
+
+		var resp todoistCompletedResponse
+		if err := json.Unmarshal(data, &resp); err != nil {
+			return fmt.Errorf("parsing response: %w", err)
+		}
+
+		for _, task := range resp.Items {
+			r := s.toReminder(&task)
+			r.Completed = true
+			if !filter.Matches(r) && !filter.MatchesWithParent(r) {
+				continue
+			}
+			if err := fn(r); err != nil {
+				return err
+			}
+		}
+
+		if resp.NextCursor == "" {
+			return nil
+		}
+		cursor = resp.NextCursor
+	}
+}
@@ -0,0 +1,127 @@
+package todoist
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shaneoxm/recall/internal/protocol"
+)
+
+// dueString builds the due_string sent to Todoist. Recurring reminders use
+// Todoist's natural-language syntax, e.g. "every 2 weeks on mon, fri at
+// 09:00 starting 2024-01-15", so that closing the task rolls it forward
+// server-side.
+func dueString(r *protocol.Reminder) string {
+	if r.Recurrence == nil {
+		if r.Due == nil {
+			return ""
+		}
+		return r.Due.Format("2006-01-02 15:04")
+	}
+
+	parts := []string{recurringRule(r.Recurrence)}
+	if r.Recurrence.Count > 0 {
+		parts = append(parts, countPhrase(r.Recurrence.Count))
+	}
+	if r.Due != nil {
+		parts = append(parts, "at "+r.Due.Format("15:04"), "starting "+r.Due.Format("2006-01-02"))
+	}
+	if r.Recurrence.Until != nil {
+		parts = append(parts, "until "+r.Recurrence.Until.In(time.Local).Format("2006-01-02"))
+	}
+	return strings.Join(parts, " ")
+}
+
+// countPhrase is how Todoist limits a rule to n occurrences.
+func countPhrase(n int) string {
+	if n == 1 {
+		return "for 1 time"
+	}
+	return fmt.Sprintf("for %d times", n)
+}
+
+// recurringRule writes the frequency, interval and weekdays of rec in
+// Todoist's grammar: "every day", "every 3 months", "every workday",
+// "every mon, fri" or "every 2 weeks on mon, fri".
+func recurringRule(rec *protocol.Recurrence) string {
+	interval := max(rec.Interval, 1)
+
+	var days []string
+	for _, d := range []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday} {
+		if slices.Contains(rec.ByDay, d) {
+			days = append(days, strings.ToLower(d.String()[:3]))
+		}
+	}
+	if len(days) > 0 {
+		list := strings.Join(days, ", ")
+		switch {
+		case list == "mon, tue, wed, thu, fri" && interval == 1:
+			return "every workday"
+		case interval == 1:
+			return "every " + list
+		default:
+			return fmt.Sprintf("every %d weeks on %s", interval, list)
+		}
+	}
+
+	unit := map[protocol.Frequency]string{
+		protocol.Daily:   "day",
+		protocol.Weekly:  "week",
+		protocol.Monthly: "month",
+		protocol.Yearly:  "year",
+	}[rec.Freq]
+	if interval == 1 {
+		return "every " + unit
+	}
+	return fmt.Sprintf("every %d %ss", interval, unit)
+}
+
+// parseRecurringDue recovers the recurrence rule from a recurring task's
+// due string. Todoist keeps monthly and yearly tasks on the day of their
+// starting date, so that day becomes the rule's BYMONTHDAY. Phrasings
+// Recall does not understand yield nil; the original string is still
+// available in the reminder's metadata.
+func parseRecurringDue(s string) *protocol.Recurrence {
+	text := strings.ToLower(strings.TrimSpace(s))
+
+	var until, starting string
+	if before, after, ok := strings.Cut(text, " until "); ok {
+		text, until = before, after
+	}
+	for _, sep := range []string{" starting ", " from ", " at "} {
+		if before, after, ok := strings.Cut(text, sep); ok {
+			text = before
+			if sep != " at " && starting == "" {
+				starting, _, _ = strings.Cut(strings.TrimSpace(after), " ")
+			}
+		}
+	}
+	var count int
+	if before, after, ok := strings.Cut(text, " for "); ok {
+		// "for 5 times", or "for 1 time"
+		var n int
+		if _, err := fmt.Sscanf(after, "%d time", &n); err != nil || n < 1 {
+			return nil
+		}
+		text, count = before, n
+	}
+	text = strings.TrimSuffix(strings.TrimSpace(text), "!")
+
+	rec, err := protocol.ParseRecurrence(strings.Replace(text, "every!", "every", 1))
+	if err != nil {
+		return nil
+	}
+	rec.Count = count
+
+	if start, err := time.Parse("2006-01-02", starting); err == nil && (rec.Freq == protocol.Monthly || rec.Freq == protocol.Yearly) {
+		rec.ByMonthDay = start.Day()
+	}
+
+	if t, err := time.ParseInLocation("2006-01-02", until, time.Local); err == nil {
+		end := t.Add(24*time.Hour - time.Second) // inclusive of the whole day
+		rec.Until = &end
+	}
+	return rec
+}
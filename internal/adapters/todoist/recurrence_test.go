@@ -0,0 +1,76 @@
+package todoist
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shaneoxm/recall/internal/protocol"
+)
+
+func TestDueString(t *testing.T) {
+	due := time.Date(2024, 1, 15, 9, 0, 0, 0, time.Local)
+
+	r := protocol.NewReminder("Pay rent")
+	r.SetDue(due)
+	if got := dueString(r); got != "2024-01-15 09:00" {
+		t.Errorf("expected plain due string, got %q", got)
+	}
+
+	tests := []struct {
+		rule string
+		want string
+	}{
+		{"FREQ=DAILY", "every day at 09:00 starting 2024-01-15"},
+		{"FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR", "every workday at 09:00 starting 2024-01-15"},
+		{"FREQ=WEEKLY;INTERVAL=2", "every 2 weeks at 09:00 starting 2024-01-15"},
+		{"FREQ=WEEKLY;BYDAY=MO,FR", "every mon, fri at 09:00 starting 2024-01-15"},
+		{"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE", "every 2 weeks on mon, wed at 09:00 starting 2024-01-15"},
+		{"FREQ=DAILY;COUNT=5", "every day for 5 times at 09:00 starting 2024-01-15"},
+		{"FREQ=MONTHLY;COUNT=1", "every month for 1 time at 09:00 starting 2024-01-15"},
+		{"FREQ=YEARLY;UNTIL=20301231", "every year at 09:00 starting 2024-01-15 until 2030-12-31"},
+	}
+	for _, tt := range tests {
+		rec, err := protocol.ParseRRule(tt.rule)
+		if err != nil {
+			t.Fatalf("ParseRRule(%q) failed: %v", tt.rule, err)
+		}
+		r.SetRecurrence(rec)
+		if got := dueString(r); got != tt.want {
+			t.Errorf("%s: expected %q, got %q", tt.rule, tt.want, got)
+		}
+		if back := parseRecurringDue(tt.want); back == nil || back.String() != rec.String() {
+			t.Errorf("%s: %q parsed back as %v", tt.rule, tt.want, back)
+		}
+	}
+}
+
+func TestParseRecurringDue(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"every day", "FREQ=DAILY"},
+		{"every weekday at 09:00", "FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR"},
+		{"every 2 weeks at 09:00 starting 2024-01-15", "FREQ=WEEKLY;INTERVAL=2"},
+		{"Every Mon, Fri", "FREQ=WEEKLY;BYDAY=MO,FR"},
+		{"every! month", "FREQ=MONTHLY"},
+		{"every 2 weeks on mon, wed at 09:00", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE"},
+		{"every day for 3 times starting 2024-01-15", "FREQ=DAILY;COUNT=3"},
+		{"every month at 09:00 starting 2024-01-31", "FREQ=MONTHLY;BYMONTHDAY=31"},
+	}
+
+	for _, tt := range tests {
+		rec := parseRecurringDue(tt.in)
+		if rec == nil {
+			t.Errorf("parseRecurringDue(%q) returned nil", tt.in)
+			continue
+		}
+		if got := rec.String(); got != tt.want {
+			t.Errorf("parseRecurringDue(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	if rec := parseRecurringDue("every 3rd friday"); rec != nil {
+		t.Errorf("expected nil for unsupported phrasing, got %v", rec)
+	}
+}
@@ -0,0 +1,243 @@
+package todoist
+
+import (
+	"bytes"
+	"context"
+	crand "crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryPolicy controls how failed requests are retried. Rate limits (429),
+// server errors (5xx) and network failures are retried with exponential
+// backoff and jitter; a Retry-After header takes precedence over the
+// computed delay.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of tries, including the first.
+	// Values below 1 mean a single try.
+	MaxAttempts int
+
+	// BaseDelay is the delay before the first retry. It doubles with
+	// each attempt.
+	BaseDelay time.Duration
+
+	// MaxDelay caps the delay between attempts. A Retry-After longer than
+	// this is not waited out; the request fails with ErrRateLimited.
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy is used unless WithRetryPolicy is given.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    30 * time.Second,
+}
+
+// WithRetryPolicy sets how failed requests are retried.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(s *Store) {
+		s.retry = p
+	}
+}
+
+// APIError is an error response from the Todoist API. It unwraps to
+// ErrUnauthorized, ErrRateLimited or ErrNotFound where one applies.
+type APIError struct {
+	StatusCode int
+	Message    string
+
+	// RetryAfter is the server's requested wait, if it sent one.
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("todoist API error %d", e.StatusCode)
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	if e.RetryAfter > 0 {
+		msg += fmt.Sprintf(" (retry after %s)", e.RetryAfter)
+	}
+	return msg
+}
+
+func (e *APIError) Unwrap() error {
+	switch e.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrUnauthorized
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	case http.StatusNotFound:
+		return ErrNotFound
+	}
+	return nil
+}
+
+// doRequest sends a request to the API and returns the response body,
+// retrying according to the store's RetryPolicy. POSTs carry an
+// X-Request-Id that stays the same across retries, so Todoist can discard
+// duplicates of a create that did reach it.
+func (s *Store) doRequest(ctx context.Context, method, path string, body []byte) ([]byte, error) {
+	var requestID string
+	if method == http.MethodPost {
+		requestID = newRequestID()
+	}
+
+	attempts := max(s.retry.MaxAttempts, 1)
+	for attempt := 1; ; attempt++ {
+		data, err := s.send(ctx, method, path, body, requestID)
+		if err == nil {
+			return data, nil
+		}
+
+		delay, retryable := s.retryDelay(err, attempt)
+		if !retryable || attempt >= attempts {
+			return nil, err
+		}
+		if err := s.sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (s *Store) send(ctx context.Context, method, path string, body []byte, requestID string) ([]byte, error) {
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, s.baseURL+path, bodyReader)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+s.token)
+	req.Header.Set("Content-Type", "application/json")
+	if requestID != "" {
+		req.Header.Set("X-Request-Id", requestID)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, &networkError{err: err}
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, &networkError{err: err}
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, &APIError{
+			StatusCode: resp.StatusCode,
+			Message:    summarize(data),
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
+	}
+
+	return data, nil
+}
+
+// retryDelay reports whether err is worth retrying after the given
+// attempt, and how long to wait first.
+func (s *Store) retryDelay(err error, attempt int) (time.Duration, bool) {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return 0, false
+	}
+
+	var netErr *networkError
+	var apiErr *APIError
+	switch {
+	case errors.As(err, &netErr):
+	case errors.As(err, &apiErr):
+		if apiErr.StatusCode != http.StatusTooManyRequests && apiErr.StatusCode < 500 {
+			return 0, false
+		}
+		if apiErr.RetryAfter > 0 {
+			if s.retry.MaxDelay > 0 && apiErr.RetryAfter > s.retry.MaxDelay {
+				return 0, false
+			}
+			return apiErr.RetryAfter, true
+		}
+	default:
+		return 0, false
+	}
+
+	return backoff(s.retry, attempt), true
+}
+
+// backoff returns the exponential delay for an attempt with equal jitter:
+// half the delay is fixed and the other half random.
+func backoff(p RetryPolicy, attempt int) time.Duration {
+	d := p.BaseDelay << (attempt - 1)
+	if d <= 0 || (p.MaxDelay > 0 && d > p.MaxDelay) {
+		d = p.MaxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+	half := d / 2
+	return half + rand.N(d-half+1)
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an
+// HTTP date.
+func parseRetryAfter(v string, now time.Time) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(strings.TrimSpace(v)); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
+
+// networkError marks transport failures, which are retried.
+type networkError struct {
+	err error
+}
+
+func (e *networkError) Error() string { return "executing request: " + e.err.Error() }
+func (e *networkError) Unwrap() error { return e.err }
+
+// summarize trims an error body to something fit for an error message.
+func summarize(body []byte) string {
+	msg := strings.Join(strings.Fields(string(body)), " ")
+	if len(msg) > 200 {
+		msg = msg[:200] + "..."
+	}
+	return msg
+}
+
+func newRequestID() string {
+	var b [16]byte
+	crand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40 // version 4
+	b[8] = b[8]&0x3f | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,196 @@
+package todoist
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type todoistProject struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	InboxProject bool   `json:"inbox_project"`
+}
+
+type todoistSection struct {
+	ID        string `json:"id"`
+	ProjectID string `json:"project_id"`
+	Name      string `json:"name"`
+}
+
+type todoistLabel struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// errScopeMissing reports that the configured project or section does
+// not exist and was not created.
+var errScopeMissing = errors.New("project or section does not exist")
+
+// scopeIDs returns the IDs of the configured project and section, looking
+// them up by name on first use. Missing ones are created when create is
+// set; otherwise errScopeMissing is returned and nothing is cached, so
+// reading never leaves an empty project behind. Both IDs are empty when
+// no project or section is configured.
+func (s *Store) scopeIDs(ctx context.Context, create bool) (projectID, sectionID string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.scopeResolved {
+		return s.projectID, s.sectionID, nil
+	}
+
+	if s.project != "" || s.section != "" {
+		projectID, err = s.findProject(ctx, s.project)
+		if err != nil {
+			return "", "", err
+		}
+		if projectID == "" {
+			if !create {
+				return "", "", errScopeMissing
+			}
+			if projectID, err = s.createProject(ctx, s.project); err != nil {
+				return "", "", err
+			}
+		}
+	}
+	if s.section != "" {
+		sectionID, err = s.findSection(ctx, projectID, s.section)
+		if err != nil {
+			return "", "", err
+		}
+		if sectionID == "" {
+			if !create {
+				return "", "", errScopeMissing
+			}
+			if sectionID, err = s.createSection(ctx, projectID, s.section); err != nil {
+				return "", "", err
+			}
+		}
+	}
+
+	s.projectID, s.sectionID, s.scopeResolved = projectID, sectionID, true
+	return projectID, sectionID, nil
+}
+
+// findProject returns the ID of the named project, or of the Inbox when
+// name is empty. It is empty when there is no such project.
+func (s *Store) findProject(ctx context.Context, name string) (string, error) {
+	projects, err := fetchAll[todoistProject](ctx, s, "/projects", nil)
+	if err != nil {
+		return "", fmt.Errorf("listing projects: %w", err)
+	}
+	for _, p := range projects {
+		if (name == "" && p.InboxProject) || (name != "" && strings.EqualFold(p.Name, name)) {
+			return p.ID, nil
+		}
+	}
+	if name == "" {
+		return "", fmt.Errorf("inbox project not found")
+	}
+	return "", nil
+}
+
+// createProject creates a project and returns its ID.
+func (s *Store) createProject(ctx context.Context, name string) (string, error) {
+	body, _ := json.Marshal(map[string]string{"name": name})
+	data, err := s.doRequest(ctx, "POST", "/projects", body)
+	if err != nil {
+		return "", fmt.Errorf("creating project %q: %w", name, err)
+	}
+	var created todoistProject
+	if err := json.Unmarshal(data, &created); err != nil {
+		return "", fmt.Errorf("parsing response: %w", err)
+	}
+	return created.ID, nil
+}
+
+// findSection returns the ID of the named section in a project. It is
+// empty when there is no such section.
+func (s *Store) findSection(ctx context.Context, projectID, name string) (string, error) {
+	sections, err := fetchAll[todoistSection](ctx, s, "/sections", url.Values{"project_id": {projectID}})
+	if err != nil {
+		return "", fmt.Errorf("listing sections: %w", err)
+	}
+	for _, sec := range sections {
+		if strings.EqualFold(sec.Name, name) {
+			return sec.ID, nil
+		}
+	}
+	return "", nil
+}
+
+// createSection creates a section in a project and returns its ID.
+func (s *Store) createSection(ctx context.Context, projectID, name string) (string, error) {
+	body, _ := json.Marshal(map[string]string{"name": name, "project_id": projectID})
+	data, err := s.doRequest(ctx, "POST", "/sections", body)
+	if err != nil {
+		return "", fmt.Errorf("creating section %q: %w", name, err)
+	}
+	var created todoistSection
+	if err := json.Unmarshal(data, &created); err != nil {
+		return "", fmt.Errorf("parsing response: %w", err)
+	}
+	return created.ID, nil
+}
+
+// labelName returns the exact name of the label matching tag
+// case-insensitively. Labels are fetched once per store.
+func (s *Store) labelName(ctx context.Context, tag string) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.labels == nil {
+		labels, err := fetchAll[todoistLabel](ctx, s, "/labels", nil)
+		if err != nil {
+			return "", false, fmt.Errorf("listing labels: %w", err)
+		}
+		s.labels = make(map[string]string, len(labels))
+		for _, l := range labels {
+			s.labels[strings.ToLower(l.Name)] = l.Name
+		}
+	}
+
+	name, ok := s.labels[strings.ToLower(tag)]
+	return name, ok, nil
+}
+
+// fetchAll follows the cursors of a paginated list endpoint and returns
+// every result.
+func fetchAll[T any](ctx context.Context, s *Store, path string, query url.Values) ([]T, error) {
+	var all []T
+	cursor := ""
+	for page := 0; page < s.maxPages; page++ {
+		q := url.Values{"limit": {strconv.Itoa(s.pageSize)}}
+		for k, v := range query {
+			q[k] = v
+		}
+		if cursor != "" {
+			q.Set("cursor", cursor)
+		}
+
+		data, err := s.doRequest(ctx, "GET", path+"?"+q.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var resp struct {
+			Results    []T    `json:"results"`
+			NextCursor string `json:"next_cursor"`
+		}
+		if err := json.Unmarshal(data, &resp); err != nil {
+			return nil, fmt.Errorf("parsing response: %w", err)
+		}
+		all = append(all, resp.Results...)
+
+		if resp.NextCursor == "" {
+			return all, nil
+		}
+		cursor = resp.NextCursor
+	}
+	return nil, fmt.Errorf("stopped after %d pages", s.maxPages)
+}
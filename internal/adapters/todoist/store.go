@@ -1,39 +1,147 @@
 package todoist
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/shaneoxm/recall/internal/protocol"
 )
 
-const baseURL = "https://api.todoist.com/api/v1"
+const (
+	defaultBaseURL = "https://api.todoist.com/api/v1"
+	taskURL        = "https://app.todoist.com/app/task/"
 
-var ErrNotFound = errors.New("task not found")
+	// DefaultPageSize is the number of tasks requested per page; 200 is
+	// the API maximum.
+	DefaultPageSize = 200
+
+	// DefaultMaxPages caps how many pages List follows, guarding against
+	// a cursor that never ends.
+	DefaultMaxPages = 100
+
+	// DefaultCompletedWindow is how far back List looks for completed
+	// tasks.
+	DefaultCompletedWindow = 12 * 7 * 24 * time.Hour
+)
+
+var (
+	ErrNotFound = fmt.Errorf("task %w", protocol.ErrNotFound)
+
+	// ErrUnauthorized is returned when Todoist rejects the API token.
+	ErrUnauthorized = errors.New("todoist rejected the API token")
+
+	// ErrRateLimited is returned when requests are still being throttled
+	// after retrying.
+	ErrRateLimited = errors.New("todoist rate limit exceeded")
+)
 
 // Store implements protocol.Store using Todoist REST API.
 type Store struct {
-	token   string
-	project string // optional project name, defaults to Inbox
-	client  *http.Client
+	token    string
+	project  string // optional project name; all projects when empty
+	section  string // optional section name within the project
+	baseURL  string
+	client   *http.Client
+	retry    RetryPolicy
+	pageSize int
+	maxPages int
+
+	// completedWindow is how far back completed tasks are fetched.
+	completedWindow time.Duration
+
+	// sleep waits between retries and now reads the clock; tests
+	// replace them.
+	sleep func(ctx context.Context, d time.Duration) error
+	now   func() time.Time
+
+	// Resolved lazily and cached; see scopeIDs and labelName.
+	mu            sync.Mutex
+	scopeResolved bool
+	projectID     string
+	sectionID     string
+	labels        map[string]string
+}
+
+// Option configures a Store.
+type Option func(*Store)
+
+// WithBaseURL points the store at a different API root, such as a
+// todoisttest.Server.
+func WithBaseURL(url string) Option {
+	return func(s *Store) {
+		s.baseURL = strings.TrimSuffix(url, "/")
+	}
 }
 
-// New creates a new Todoist store with the given API token.
-func New(token string, project string) *Store {
-	return &Store{
-		token:   token,
-		project: project,
-		client:  &http.Client{Timeout: 15 * time.Second},
+// WithHTTPClient sets the HTTP client used for API requests.
+func WithHTTPClient(client *http.Client) Option {
+	return func(s *Store) {
+		s.client = client
 	}
 }
 
+// WithSection restricts the store to a section of its project, or of the
+// Inbox when no project is set.
+func WithSection(name string) Option {
+	return func(s *Store) {
+		s.section = name
+	}
+}
+
+// WithPageSize sets how many tasks are requested per page (1-200).
+func WithPageSize(n int) Option {
+	return func(s *Store) {
+		s.pageSize = n
+	}
+}
+
+// WithMaxPages sets how many pages List follows before giving up with an
+// error.
+func WithMaxPages(n int) Option {
+	return func(s *Store) {
+		s.maxPages = n
+	}
+}
+
+// WithCompletedWindow sets how far back List looks for completed tasks
+// when the filter includes them.
+func WithCompletedWindow(d time.Duration) Option {
+	return func(s *Store) {
+		s.completedWindow = d
+	}
+}
+
+// New creates a new Todoist store with the given API token. When project
+// is set, new tasks are created in that project and List only returns its
+// tasks; the project is created if it does not exist.
+func New(token string, project string, opts ...Option) *Store {
+	s := &Store{
+		token:    token,
+		project:  project,
+		baseURL:  defaultBaseURL,
+		client:   &http.Client{Timeout: 15 * time.Second},
+		retry:    DefaultRetryPolicy,
+		pageSize: DefaultPageSize,
+		maxPages: DefaultMaxPages,
+		sleep:    sleepContext,
+		now:      time.Now,
+
+		completedWindow: DefaultCompletedWindow,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
 // todoistTask represents a Todoist task.
 type todoistTask struct {
 	ID          string      `json:"id"`
@@ -42,15 +150,20 @@ type todoistTask struct {
 	Due         *dueDateObj `json:"due,omitempty"`
 	Priority    int         `json:"priority,omitempty"` // 1=normal, 2, 3, 4=urgent
 	Labels      []string    `json:"labels,omitempty"`
-	IsCompleted bool        `json:"is_completed"`
-	CreatedAt   string      `json:"created_at"`
+	Checked     bool        `json:"checked"`
+	AddedAt     string      `json:"added_at"`
+	UpdatedAt   string      `json:"updated_at"`
+	CompletedAt string      `json:"completed_at"`
 	ParentID    string      `json:"parent_id,omitempty"`
+	ProjectID   string      `json:"project_id,omitempty"`
+	SectionID   string      `json:"section_id,omitempty"`
 }
 
 type dueDateObj struct {
-	String   string `json:"string,omitempty"`
-	Date     string `json:"date,omitempty"`
-	Datetime string `json:"datetime,omitempty"`
+	String      string `json:"string,omitempty"`
+	Date        string `json:"date,omitempty"`
+	Datetime    string `json:"datetime,omitempty"`
+	IsRecurring bool   `json:"is_recurring,omitempty"`
 }
 
 type createTaskRequest struct {
@@ -59,20 +172,42 @@ type createTaskRequest struct {
 	DueString   string   `json:"due_string,omitempty"`
 	Priority    int      `json:"priority,omitempty"`
 	Labels      []string `json:"labels,omitempty"`
+	ParentID    string   `json:"parent_id,omitempty"`
+	ProjectID   string   `json:"project_id,omitempty"`
+	SectionID   string   `json:"section_id,omitempty"`
+}
+
+// updateTaskRequest is the body of a task update. Unlike createTaskRequest,
+// every field is always sent, so clearing notes, tags or priority in
+// Recall clears them in Todoist too.
+type updateTaskRequest struct {
+	Content     string   `json:"content"`
+	Description string   `json:"description"`
+	DueString   string   `json:"due_string"`
+	Priority    int      `json:"priority"`
+	Labels      []string `json:"labels"`
 }
 
-// Add creates a new task in Todoist.
+// Add creates a new task in Todoist and sets reminder.ID to the task ID.
+// Subtasks are created under their parent, in the parent's project.
 func (s *Store) Add(ctx context.Context, reminder *protocol.Reminder) error {
 	req := createTaskRequest{
 		Content:     reminder.Title,
 		Description: s.buildDescription(reminder),
 		Labels:      reminder.Tags,
+		ParentID:    reminder.ParentID,
 	}
 
-	if reminder.Due != nil {
-		req.DueString = reminder.Due.Format("2006-01-02 15:04")
+	if reminder.ParentID == "" {
+		projectID, sectionID, err := s.scopeIDs(ctx, true)
+		if err != nil {
+			return err
+		}
+		req.ProjectID, req.SectionID = projectID, sectionID
 	}
 
+	req.DueString = dueString(reminder)
+
 	if reminder.Priority > 0 {
 		// Todoist: 1=normal, 4=urgent. Ours: 1=low, 3=high
 		// Map: our 1->2, our 2->3, our 3->4
@@ -84,12 +219,41 @@ func (s *Store) Add(ctx context.Context, reminder *protocol.Reminder) error {
 		return fmt.Errorf("marshaling request: %w", err)
 	}
 
-	_, err = s.doRequest(ctx, "POST", "/tasks", body)
-	return err
+	data, err := s.doRequest(ctx, "POST", "/tasks", body)
+	if err != nil {
+		return err
+	}
+
+	// Adopt Todoist's ID so callers can address the new task, and its
+	// timestamps so the reminder matches what Get returns.
+	var task todoistTask
+	if err := json.Unmarshal(data, &task); err != nil {
+		return fmt.Errorf("parsing response: %w", err)
+	}
+	if task.ID != "" {
+		created := s.toReminder(&task)
+		reminder.ID = created.ID
+		reminder.Metadata = created.Metadata
+		if !created.CreatedAt.IsZero() {
+			reminder.CreatedAt = created.CreatedAt
+		}
+		if !created.UpdatedAt.IsZero() {
+			reminder.UpdatedAt = created.UpdatedAt
+		}
+	}
+	return nil
 }
 
 // Get retrieves a task by ID.
 func (s *Store) Get(ctx context.Context, id string) (*protocol.Reminder, error) {
+	task, err := s.getTask(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.toReminder(task), nil
+}
+
+func (s *Store) getTask(ctx context.Context, id string) (*todoistTask, error) {
 	data, err := s.doRequest(ctx, "GET", "/tasks/"+id, nil)
 	if err != nil {
 		return nil, err
@@ -99,56 +263,210 @@ func (s *Store) Get(ctx context.Context, id string) (*protocol.Reminder, error)
 	if err := json.Unmarshal(data, &task); err != nil {
 		return nil, fmt.Errorf("parsing response: %w", err)
 	}
-
-	return s.toReminder(&task), nil
+	return &task, nil
 }
 
-// todoistListResponse wraps the Todoist v1 API response.
+// todoistListResponse is one page of a Todoist v1 list endpoint.
 type todoistListResponse struct {
-	Results []todoistTask `json:"results"`
+	Results    []todoistTask `json:"results"`
+	NextCursor string        `json:"next_cursor"`
 }
 
 // List returns all active tasks from Todoist.
 // Note: Todoist API filter is broken, so we fetch all tasks and filter client-side.
 func (s *Store) List(ctx context.Context, filter *protocol.ListFilter) ([]*protocol.Reminder, error) {
-	// Always fetch all tasks - API filter doesn't work correctly
-	data, err := s.doRequest(ctx, "GET", "/tasks", nil)
+	var reminders []*protocol.Reminder
+	err := s.ListEach(ctx, filter, func(r *protocol.Reminder) error {
+		reminders = append(reminders, r)
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
+	return reminders, nil
+}
 
-	var resp todoistListResponse
-	if err := json.Unmarshal(data, &resp); err != nil {
-		return nil, fmt.Errorf("parsing response: %w", err)
+// ListEach calls fn for each task matching filter, page by page, so
+// callers can start on the first page before the rest arrive. Active
+// tasks come first; when the filter includes completed tasks, those
+// completed within the completed window follow. Undated subtasks of
+// listed tasks come last, as their parent may be on a later page.
+func (s *Store) ListEach(ctx context.Context, filter *protocol.ListFilter, fn func(*protocol.Reminder) error) error {
+	scope, err := s.listScope(ctx, filter)
+	if errors.Is(err, errScopeMissing) {
+		// Nothing can be in a project or section that does not exist.
+		return nil
+	}
+	if err != nil {
+		return err
 	}
 
-	tasks := resp.Results
+	listed := map[string]bool{}
+	var held []*protocol.Reminder
+	visit := func(r *protocol.Reminder) error {
+		if !filter.Matches(r) {
+			held = append(held, r)
+			return nil
+		}
+		listed[r.ID] = true
+		return fn(r)
+	}
+
+	seen := map[string]bool{}
+	err = s.listActive(ctx, scope, filter, func(r *protocol.Reminder) error {
+		seen[r.ID] = true
+		return visit(r)
+	})
+	if err == nil && filter != nil && filter.IncludeCompleted {
+		err = s.listCompleted(ctx, scope, filter, func(r *protocol.Reminder) error {
+			// Completed occurrences of a recurring task share its ID.
+			if seen[r.ID] {
+				return nil
+			}
+			return visit(r)
+		})
+	}
+	if err != nil {
+		return err
+	}
 
-	var reminders []*protocol.Reminder
-	for _, task := range tasks {
-		r := s.toReminder(&task)
-		if s.matchesFilter(r, filter) {
-			reminders = append(reminders, r)
+	for _, r := range protocol.ListedSubtasks(held, listed) {
+		if err := fn(r); err != nil {
+			return err
 		}
 	}
+	return nil
+}
 
-	return reminders, nil
+// listActive pages through GET /tasks.
+func (s *Store) listActive(ctx context.Context, scope url.Values, filter *protocol.ListFilter, fn func(*protocol.Reminder) error) error {
+	cursor := ""
+	for page := 0; ; page++ {
+		if page == s.maxPages {
+			return fmt.Errorf("listing tasks: stopped after %d pages", s.maxPages)
+		}
+
+		query := url.Values{"limit": {strconv.Itoa(s.pageSize)}}
+		for k, v := range scope {
+			query[k] = v
+		}
+		if cursor != "" {
+			query.Set("cursor", cursor)
+		}
+
+		data, err := s.doRequest(ctx, "GET", "/tasks?"+query.Encode(), nil)
+		if err != nil {
+			return err
+		}
+
+		var resp todoistListResponse
+		if err := json.Unmarshal(data, &resp); err != nil {
+			return fmt.Errorf("parsing response: %w", err)
+		}
+
+		for _, task := range resp.Results {
+			r := s.toReminder(&task)
+			if !filter.Matches(r) && !filter.MatchesWithParent(r) {
+				continue
+			}
+			if err := fn(r); err != nil {
+				return err
+			}
+		}
+
+		if resp.NextCursor == "" {
+			return nil
+		}
+		cursor = resp.NextCursor
+	}
 }
 
-// Update modifies an existing task.
+// listScope returns the query parameters that narrow GET /tasks to the
+// configured project and section, and to a single tag when the filter
+// asks for exactly one that exists as a label. It returns errScopeMissing
+// when the project or section has not been created yet.
+func (s *Store) listScope(ctx context.Context, filter *protocol.ListFilter) (url.Values, error) {
+	projectID, sectionID, err := s.scopeIDs(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	scope := url.Values{}
+	if projectID != "" {
+		scope.Set("project_id", projectID)
+	}
+	if sectionID != "" {
+		scope.Set("section_id", sectionID)
+	}
+
+	if filter != nil && len(filter.Tags) == 1 {
+		name, ok, err := s.labelName(ctx, filter.Tags[0])
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			scope.Set("label", name)
+		}
+	}
+	return scope, nil
+}
+
+// Update modifies an existing task. Todoist ignores parent_id on update,
+// so a changed ParentID is applied by moving the task, and a completed
+// task given an open reminder is reopened. Completing goes through
+// Complete.
 func (s *Store) Update(ctx context.Context, reminder *protocol.Reminder) error {
-	req := createTaskRequest{
+	current, err := s.getTask(ctx, reminder.ID)
+	if err != nil {
+		return err
+	}
+
+	req := updateTaskRequest{
 		Content:     reminder.Title,
 		Description: s.buildDescription(reminder),
+		DueString:   dueString(reminder),
+		Priority:    reminder.Priority + 1, // Todoist's 1 is no priority
 		Labels:      reminder.Tags,
 	}
+	if req.DueString == "" {
+		req.DueString = "no date"
+	}
+	if req.Labels == nil {
+		req.Labels = []string{}
+	}
 
-	if reminder.Due != nil {
-		req.DueString = reminder.Due.Format("2006-01-02 15:04")
+	body, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("marshaling request: %w", err)
 	}
 
-	if reminder.Priority > 0 {
-		req.Priority = reminder.Priority + 1
+	if _, err := s.doRequest(ctx, "POST", "/tasks/"+reminder.ID, body); err != nil {
+		return err
+	}
+
+	if current.Checked && !reminder.Completed {
+		if _, err := s.doRequest(ctx, "POST", "/tasks/"+reminder.ID+"/reopen", nil); err != nil {
+			return fmt.Errorf("reopening task: %w", err)
+		}
+	}
+
+	if reminder.ParentID != current.ParentID {
+		return s.move(ctx, current, reminder.ParentID)
+	}
+	return nil
+}
+
+// move makes a task a subtask of parentID, or a top-level task in its
+// section or project when parentID is empty.
+func (s *Store) move(ctx context.Context, task *todoistTask, parentID string) error {
+	req := map[string]string{}
+	switch {
+	case parentID != "":
+		req["parent_id"] = parentID
+	case task.SectionID != "":
+		req["section_id"] = task.SectionID
+	default:
+		req["project_id"] = task.ProjectID
 	}
 
 	body, err := json.Marshal(req)
@@ -156,8 +474,10 @@ func (s *Store) Update(ctx context.Context, reminder *protocol.Reminder) error {
 		return fmt.Errorf("marshaling request: %w", err)
 	}
 
-	_, err = s.doRequest(ctx, "POST", "/tasks/"+reminder.ID, body)
-	return err
+	if _, err := s.doRequest(ctx, "POST", "/tasks/"+task.ID+"/move", body); err != nil {
+		return fmt.Errorf("moving task: %w", err)
+	}
+	return nil
 }
 
 // Delete removes a task by ID.
@@ -172,70 +492,93 @@ func (s *Store) Complete(ctx context.Context, id string) error {
 	return err
 }
 
-func (s *Store) doRequest(ctx context.Context, method, path string, body []byte) ([]byte, error) {
-	var bodyReader io.Reader
-	if body != nil {
-		bodyReader = bytes.NewReader(body)
-	}
+func (s *Store) buildDescription(r *protocol.Reminder) string {
+	var parts []string
 
-	req, err := http.NewRequestWithContext(ctx, method, baseURL+path, bodyReader)
-	if err != nil {
-		return nil, fmt.Errorf("creating request: %w", err)
+	if r.Notes != "" {
+		parts = append(parts, r.Notes)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+s.token)
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := s.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("executing request: %w", err)
+	if len(r.Links) > 0 {
+		if len(parts) > 0 {
+			parts = append(parts, "")
+		}
+		parts = append(parts, "Links:")
+		for _, link := range r.Links {
+			parts = append(parts, "- "+link)
+		}
 	}
-	defer resp.Body.Close()
 
-	data, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("reading response: %w", err)
-	}
+	return strings.Join(parts, "\n")
+}
 
-	if resp.StatusCode == 404 {
-		return nil, ErrNotFound
+// parseDescription splits a description written by buildDescription back
+// into notes and links. A description without a trailing "Links:" block
+// is all notes.
+func parseDescription(desc string) (string, []string) {
+	notes, block := "", strings.TrimRight(desc, "\n")
+	if i := strings.LastIndex(block, "\n\nLinks:\n"); i >= 0 {
+		notes, block = block[:i], block[i+2:]
 	}
 
-	if resp.StatusCode >= 400 {
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(data))
+	rest, ok := strings.CutPrefix(block, "Links:\n")
+	if !ok {
+		return desc, nil
 	}
 
-	return data, nil
+	var links []string
+	for _, line := range strings.Split(rest, "\n") {
+		link, ok := strings.CutPrefix(line, "- ")
+		if !ok || strings.TrimSpace(link) == "" {
+			return desc, nil
+		}
+		links = append(links, link)
+	}
+	return notes, links
 }
 
-func (s *Store) buildDescription(r *protocol.Reminder) string {
-	var parts []string
-
-	if r.Notes != "" {
-		parts = append(parts, r.Notes)
+// parseDueDate reads a task's due date. The v1 API puts everything in
+// date: "2006-01-02" for all-day tasks, "2006-01-02T15:04:05" for floating
+// times and "2006-01-02T15:04:05Z" for times fixed to a timezone. Older
+// responses carry a separate datetime field. All-day and floating dates
+// are read in local time.
+func parseDueDate(due *dueDateObj) *time.Time {
+	if due.Datetime != "" {
+		if t, err := time.Parse(time.RFC3339, due.Datetime); err == nil {
+			return &t
+		}
 	}
 
-	if len(r.Links) > 0 {
-		parts = append(parts, "")
-		parts = append(parts, "Links:")
-		for _, link := range r.Links {
-			parts = append(parts, "- "+link)
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, due.Date, time.Local); err == nil {
+			return &t
 		}
 	}
+	return nil
+}
 
-	return strings.Join(parts, "\n")
+// parseTimestamp reads one of the RFC 3339 timestamps on a task, such as
+// added_at or completed_at.
+func parseTimestamp(v string) (time.Time, bool) {
+	if v == "" {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	return t, err == nil
 }
 
 func (s *Store) toReminder(task *todoistTask) *protocol.Reminder {
 	r := &protocol.Reminder{
 		ID:        task.ID,
 		Title:     task.Content,
-		Notes:     task.Description,
-		Tags:      task.Labels,
-		Completed: task.IsCompleted,
+		Completed: task.Checked,
 		ParentID:  task.ParentID,
 		IsSubtask: task.ParentID != "",
 	}
+	r.Notes, r.Links = parseDescription(task.Description)
+	if len(task.Labels) > 0 {
+		r.Tags = task.Labels
+	}
 
 	// Parse priority (Todoist 1=normal, 4=urgent -> ours 0=none, 3=high)
 	if task.Priority > 1 {
@@ -244,93 +587,34 @@ func (s *Store) toReminder(task *todoistTask) *protocol.Reminder {
 
 	// Parse due date
 	if task.Due != nil {
-		if task.Due.Datetime != "" {
-			if t, err := time.Parse(time.RFC3339, task.Due.Datetime); err == nil {
-				// Convert to local time for comparison
-				r.Due = &t
-			}
-		} else if task.Due.Date != "" {
-			// Date is all-day, parse as local time at midnight
-			if t, err := time.ParseInLocation("2006-01-02", task.Due.Date, time.Local); err == nil {
-				r.Due = &t
-			}
-		}
+		r.Due = parseDueDate(task.Due)
 	}
 
-	// Parse created date
-	if task.CreatedAt != "" {
-		if t, err := time.Parse(time.RFC3339, task.CreatedAt); err == nil {
-			r.CreatedAt = t
-		}
+	// Recurring tasks keep their rule in the natural-language due string
+	if task.Due != nil && task.Due.IsRecurring {
+		r.Recurrence = parseRecurringDue(task.Due.String)
 	}
 
-	return r
-}
-
-func (s *Store) matchesFilter(r *protocol.Reminder, filter *protocol.ListFilter) bool {
-	if filter == nil {
-		return true
+	if t, ok := parseTimestamp(task.AddedAt); ok {
+		r.CreatedAt = t
 	}
-
-	if !filter.IncludeCompleted && r.Completed {
-		return false
+	if t, ok := parseTimestamp(task.UpdatedAt); ok {
+		r.UpdatedAt = t
 	}
-
-	if len(filter.Tags) > 0 {
-		hasTag := false
-		for _, ft := range filter.Tags {
-			for _, rt := range r.Tags {
-				if strings.EqualFold(ft, rt) {
-					hasTag = true
-					break
-				}
-			}
-		}
-		if !hasTag {
-			return false
-		}
+	if t, ok := parseTimestamp(task.CompletedAt); ok {
+		r.CompletedAt = &t
 	}
 
-	if filter.Search != "" {
-		search := strings.ToLower(filter.Search)
-		if !strings.Contains(strings.ToLower(r.Title), search) &&
-			!strings.Contains(strings.ToLower(r.Notes), search) {
-			return false
-		}
+	r.Metadata = map[string]string{"url": taskURL + task.ID}
+	if task.ProjectID != "" {
+		r.Metadata["project_id"] = task.ProjectID
 	}
-
-	// Date range filtering - use date-only comparison (ignore time)
-	if filter.DueAfter != nil {
-		if r.Due == nil {
-			// Exclude tasks without dates, but allow subtasks (they inherit parent's date)
-			if !r.IsSubtask {
-				return false
-			}
-		} else {
-			// Compare date components only - task must be due on or after DueAfter
-			afterDate := time.Date(filter.DueAfter.Year(), filter.DueAfter.Month(), filter.DueAfter.Day(), 0, 0, 0, 0, filter.DueAfter.Location())
-			dueDate := time.Date(r.Due.Year(), r.Due.Month(), r.Due.Day(), 0, 0, 0, 0, r.Due.Location())
-			if dueDate.Before(afterDate) {
-				return false
-			}
-		}
+	if task.SectionID != "" {
+		r.Metadata["section_id"] = task.SectionID
 	}
-
-	if filter.DueBefore != nil {
-		if r.Due == nil {
-			// Allow subtasks without dates (they inherit parent's date)
-			if !r.IsSubtask {
-				return false
-			}
-		} else {
-			// Compare date components only
-			beforeDate := time.Date(filter.DueBefore.Year(), filter.DueBefore.Month(), filter.DueBefore.Day(), 0, 0, 0, 0, filter.DueBefore.Location())
-			dueDate := time.Date(r.Due.Year(), r.Due.Month(), r.Due.Day(), 0, 0, 0, 0, r.Due.Location())
-			if !dueDate.Before(beforeDate) {
-				return false
-			}
-		}
+	if task.Due != nil && task.Due.String != "" {
+		r.Metadata["due_string"] = task.Due.String
 	}
 
-	return true
+	return r
 }
@@ -0,0 +1,825 @@
+package todoist
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/shaneoxm/recall/internal/adapters/todoist/todoisttest"
+	"github.com/shaneoxm/recall/internal/protocol"
+	"github.com/shaneoxm/recall/internal/protocol/storetest"
+)
+
+func newTestStore(t *testing.T) (*Store, *todoisttest.Server) {
+	t.Helper()
+	srv := todoisttest.NewServer()
+	t.Cleanup(srv.Close)
+
+	store := New(srv.Token, "", WithBaseURL(srv.URL), WithHTTPClient(srv.Client()))
+	store.sleep = func(ctx context.Context, d time.Duration) error { return nil }
+	return store, srv
+}
+
+func TestStore_AddSetsID(t *testing.T) {
+	store, srv := newTestStore(t)
+
+	r := protocol.NewReminder("Call mom")
+	if err := store.Add(context.Background(), r); err != nil {
+		t.Fatalf("failed to add: %v", err)
+	}
+
+	task, ok := srv.Task(r.ID)
+	if !ok {
+		t.Fatalf("expected reminder ID %q to be the Todoist task ID", r.ID)
+	}
+	if task.Content != "Call mom" {
+		t.Errorf("expected content %q, got %q", "Call mom", task.Content)
+	}
+}
+
+func TestStore_PriorityMapping(t *testing.T) {
+	store, srv := newTestStore(t)
+	ctx := context.Background()
+
+	for ours, theirs := range []int{1, 2, 3, 4} {
+		r := protocol.NewReminder("Task")
+		r.SetPriority(ours)
+		if err := store.Add(ctx, r); err != nil {
+			t.Fatalf("failed to add: %v", err)
+		}
+
+		task, _ := srv.Task(r.ID)
+		if task.Priority != theirs {
+			t.Errorf("priority %d: expected Todoist priority %d, got %d", ours, theirs, task.Priority)
+		}
+
+		got, err := store.Get(ctx, r.ID)
+		if err != nil {
+			t.Fatalf("failed to get: %v", err)
+		}
+		if got.Priority != ours {
+			t.Errorf("Todoist priority %d: expected priority %d, got %d", theirs, ours, got.Priority)
+		}
+	}
+}
+
+func TestStore_Description(t *testing.T) {
+	store, srv := newTestStore(t)
+
+	r := protocol.NewReminder("Review PR")
+	r.SetNotes("Check error handling")
+	r.AddLink("https://github.com/shaneoxm/recall/pull/1")
+	r.AddLink("https://example.com/spec")
+	if err := store.Add(context.Background(), r); err != nil {
+		t.Fatalf("failed to add: %v", err)
+	}
+
+	task, _ := srv.Task(r.ID)
+	want := "Check error handling\n\nLinks:\n- https://github.com/shaneoxm/recall/pull/1\n- https://example.com/spec"
+	if task.Description != want {
+		t.Errorf("expected description:\n%s\ngot:\n%s", want, task.Description)
+	}
+}
+
+func TestParseDescription(t *testing.T) {
+	tests := []struct {
+		desc  string
+		notes string
+		links []string
+	}{
+		{"", "", nil},
+		{"Just notes", "Just notes", nil},
+		{"Notes\n\nLinks:\n- https://a\n- https://b", "Notes", []string{"https://a", "https://b"}},
+		{"Links:\n- https://a", "", []string{"https://a"}},
+		{"Line one\n\nLine two\n\nLinks:\n- https://a\n", "Line one\n\nLine two", []string{"https://a"}},
+		{"Links:\nnot a list", "Links:\nnot a list", nil},
+		{"See Links:\n- below", "See Links:\n- below", nil},
+	}
+
+	for _, tt := range tests {
+		notes, links := parseDescription(tt.desc)
+		if notes != tt.notes || !slices.Equal(links, tt.links) {
+			t.Errorf("parseDescription(%q) = %q, %q; expected %q, %q", tt.desc, notes, links, tt.notes, tt.links)
+		}
+	}
+}
+
+func TestStore_RoundTrip(t *testing.T) {
+	store, _ := newTestStore(t)
+	ctx := context.Background()
+
+	r := protocol.NewReminder("Review PR")
+	r.SetNotes("Check error handling")
+	r.AddLink("https://github.com/shaneoxm/recall/pull/1")
+	r.AddTag("work")
+	r.SetPriority(2)
+	r.SetDue(time.Date(2026, 3, 15, 9, 45, 0, 0, time.Local))
+	if err := store.Add(ctx, r); err != nil {
+		t.Fatalf("failed to add: %v", err)
+	}
+
+	got, err := store.Get(ctx, r.ID)
+	if err != nil {
+		t.Fatalf("failed to get: %v", err)
+	}
+
+	if got.Title != r.Title || got.Notes != r.Notes || got.Priority != r.Priority {
+		t.Errorf("expected %q/%q/%d, got %q/%q/%d", r.Title, r.Notes, r.Priority, got.Title, got.Notes, got.Priority)
+	}
+	if !slices.Equal(got.Links, r.Links) {
+		t.Errorf("expected links %v, got %v", r.Links, got.Links)
+	}
+	if !slices.Equal(got.Tags, r.Tags) {
+		t.Errorf("expected tags %v, got %v", r.Tags, got.Tags)
+	}
+	if got.Due == nil || !got.Due.Equal(*r.Due) {
+		t.Errorf("expected due %v, got %v", r.Due, got.Due)
+	}
+	if !got.CreatedAt.Equal(r.CreatedAt) || !got.UpdatedAt.Equal(r.UpdatedAt) {
+		t.Errorf("expected timestamps %v/%v, got %v/%v", r.CreatedAt, r.UpdatedAt, got.CreatedAt, got.UpdatedAt)
+	}
+	if got.Metadata["url"] != r.Metadata["url"] {
+		t.Errorf("expected metadata %v, got %v", r.Metadata, got.Metadata)
+	}
+}
+
+func TestStore_CompletedAt(t *testing.T) {
+	store, srv := newTestStore(t)
+	ctx := context.Background()
+
+	r := protocol.NewReminder("Ship it")
+	if err := store.Add(ctx, r); err != nil {
+		t.Fatalf("failed to add: %v", err)
+	}
+	if err := store.Complete(ctx, r.ID); err != nil {
+		t.Fatalf("failed to complete: %v", err)
+	}
+
+	got, err := store.Get(ctx, r.ID)
+	if err != nil {
+		t.Fatalf("failed to get: %v", err)
+	}
+	task, _ := srv.Task(r.ID)
+	if !got.Completed || got.CompletedAt == nil {
+		t.Fatalf("expected a completed reminder with CompletedAt, got %+v", got)
+	}
+	if got.CompletedAt.UTC().Format("2006-01-02T15:04:05.000000Z") != *task.CompletedAt {
+		t.Errorf("expected CompletedAt %s, got %s", *task.CompletedAt, got.CompletedAt)
+	}
+}
+
+func TestStore_Subtasks(t *testing.T) {
+	store, srv := newTestStore(t)
+	ctx := context.Background()
+
+	parent := protocol.NewReminder("Release")
+	if err := store.Add(ctx, parent); err != nil {
+		t.Fatalf("failed to add parent: %v", err)
+	}
+	child := protocol.NewReminder("Tag version")
+	child.ParentID, child.IsSubtask = parent.ID, true
+	if err := store.Add(ctx, child); err != nil {
+		t.Fatalf("failed to add subtask: %v", err)
+	}
+
+	task, _ := srv.Task(child.ID)
+	if task.ParentID == nil || *task.ParentID != parent.ID {
+		t.Fatalf("expected subtask of %s, got parent %v", parent.ID, task.ParentID)
+	}
+	got, err := store.Get(ctx, child.ID)
+	if err != nil {
+		t.Fatalf("failed to get: %v", err)
+	}
+	if got.ParentID != parent.ID || !got.IsSubtask {
+		t.Errorf("expected subtask of %s, got %+v", parent.ID, got)
+	}
+
+	// Promote it to a top-level task.
+	got.ParentID, got.IsSubtask = "", false
+	if err := store.Update(ctx, got); err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+	if task, _ := srv.Task(child.ID); task.ParentID != nil {
+		t.Errorf("expected top-level task, got parent %s", *task.ParentID)
+	}
+
+	// And back again.
+	got.ParentID = parent.ID
+	if err := store.Update(ctx, got); err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+	if task, _ := srv.Task(child.ID); task.ParentID == nil || *task.ParentID != parent.ID {
+		t.Errorf("expected subtask of %s, got parent %v", parent.ID, task.ParentID)
+	}
+}
+
+func TestStore_UpdateClearsDue(t *testing.T) {
+	store, srv := newTestStore(t)
+	ctx := context.Background()
+
+	r := protocol.NewReminder("Dentist")
+	r.SetDue(time.Date(2026, 3, 15, 0, 0, 0, 0, time.Local))
+	if err := store.Add(ctx, r); err != nil {
+		t.Fatalf("failed to add: %v", err)
+	}
+
+	r.Due = nil
+	if err := store.Update(ctx, r); err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+	if task, _ := srv.Task(r.ID); task.Due != nil {
+		t.Errorf("expected due date to be cleared, got %+v", task.Due)
+	}
+}
+
+func TestStore_UpdateClearsFields(t *testing.T) {
+	store, srv := newTestStore(t)
+	ctx := context.Background()
+
+	r := protocol.NewReminder("Deploy")
+	r.SetNotes("Check the dashboards")
+	r.AddTag("work")
+	r.SetPriority(3)
+	if err := store.Add(ctx, r); err != nil {
+		t.Fatalf("failed to add: %v", err)
+	}
+
+	r.Notes, r.Tags, r.Priority = "", nil, 0
+	if err := store.Update(ctx, r); err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+
+	task, _ := srv.Task(r.ID)
+	if task.Description != "" || len(task.Labels) != 0 || task.Priority != 1 {
+		t.Errorf("expected notes, labels and priority to be cleared, got %+v", task)
+	}
+}
+
+func TestStore_UpdateReopens(t *testing.T) {
+	store, srv := newTestStore(t)
+	ctx := context.Background()
+
+	r := protocol.NewReminder("Deploy")
+	if err := store.Add(ctx, r); err != nil {
+		t.Fatalf("failed to add: %v", err)
+	}
+	if err := store.Complete(ctx, r.ID); err != nil {
+		t.Fatalf("failed to complete: %v", err)
+	}
+
+	r.Completed, r.CompletedAt = false, nil
+	if err := store.Update(ctx, r); err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+	if task, _ := srv.Task(r.ID); task.Checked {
+		t.Error("expected the task to be reopened")
+	}
+}
+
+func TestStore_Labels(t *testing.T) {
+	store, srv := newTestStore(t)
+
+	r := protocol.NewReminder("Deploy")
+	r.AddTag("work")
+	r.AddTag("ops")
+	if err := store.Add(context.Background(), r); err != nil {
+		t.Fatalf("failed to add: %v", err)
+	}
+
+	task, _ := srv.Task(r.ID)
+	if !slices.Equal(task.Labels, []string{"work", "ops"}) {
+		t.Errorf("expected labels [work ops], got %v", task.Labels)
+	}
+	if labels := srv.Labels(); len(labels) != 2 {
+		t.Errorf("expected 2 labels to exist, got %v", labels)
+	}
+}
+
+func TestStore_DueParsing(t *testing.T) {
+	store, srv := newTestStore(t)
+	utc := "UTC"
+
+	tests := []struct {
+		name string
+		due  *todoisttest.Due
+		want time.Time
+	}{
+		{"all day", &todoisttest.Due{Date: "2026-03-15"}, time.Date(2026, 3, 15, 0, 0, 0, 0, time.Local)},
+		{"floating", &todoisttest.Due{Date: "2026-03-15T14:30:00"}, time.Date(2026, 3, 15, 14, 30, 0, 0, time.Local)},
+		{"fixed zone", &todoisttest.Due{Date: "2026-03-15T14:30:00Z", Timezone: &utc}, time.Date(2026, 3, 15, 14, 30, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		task := srv.AddTask(todoisttest.Task{Content: tt.name, Due: tt.due})
+
+		got, err := store.Get(context.Background(), task.ID)
+		if err != nil {
+			t.Fatalf("%s: failed to get: %v", tt.name, err)
+		}
+		if got.Due == nil || !got.Due.Equal(tt.want) {
+			t.Errorf("%s: expected due %s, got %v", tt.name, tt.want, got.Due)
+		}
+	}
+}
+
+func TestStore_DueRoundTrip(t *testing.T) {
+	store, _ := newTestStore(t)
+	ctx := context.Background()
+
+	due := time.Date(2026, 3, 15, 9, 45, 0, 0, time.Local)
+	r := protocol.NewReminder("Dentist")
+	r.SetDue(due)
+	if err := store.Add(ctx, r); err != nil {
+		t.Fatalf("failed to add: %v", err)
+	}
+
+	got, err := store.Get(ctx, r.ID)
+	if err != nil {
+		t.Fatalf("failed to get: %v", err)
+	}
+	if got.Due == nil || !got.Due.Equal(due) {
+		t.Errorf("expected due %s, got %v", due, got.Due)
+	}
+}
+
+func TestStore_NotFound(t *testing.T) {
+	store, _ := newTestStore(t)
+	ctx := context.Background()
+
+	if _, err := store.Get(ctx, "404"); !errors.Is(err, protocol.ErrNotFound) {
+		t.Errorf("Get: expected ErrNotFound, got %v", err)
+	}
+	if err := store.Complete(ctx, "404"); !errors.Is(err, protocol.ErrNotFound) {
+		t.Errorf("Complete: expected ErrNotFound, got %v", err)
+	}
+	if err := store.Delete(ctx, "404"); !errors.Is(err, protocol.ErrNotFound) {
+		t.Errorf("Delete: expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestStore_RetriesServerErrors(t *testing.T) {
+	store, srv := newTestStore(t)
+	ctx := context.Background()
+
+	srv.Inject(todoisttest.Fault{Method: http.MethodGet, Path: "/tasks", Status: http.StatusServiceUnavailable})
+	if _, err := store.List(ctx, nil); err != nil {
+		t.Fatalf("expected the retry to succeed, got %v", err)
+	}
+	if n := len(srv.Requests()); n != 2 {
+		t.Errorf("expected 2 attempts, got %d", n)
+	}
+
+	srv.Inject(todoisttest.Fault{Path: "/tasks", Status: http.StatusBadGateway, Times: 10})
+	_, err := store.List(ctx, nil)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadGateway {
+		t.Fatalf("expected a 502 APIError, got %v", err)
+	}
+	if n := len(srv.Requests()) - 2; n != DefaultRetryPolicy.MaxAttempts {
+		t.Errorf("expected %d attempts, got %d", DefaultRetryPolicy.MaxAttempts, n)
+	}
+}
+
+func TestStore_RetryAfter(t *testing.T) {
+	store, srv := newTestStore(t)
+	var slept []time.Duration
+	store.sleep = func(ctx context.Context, d time.Duration) error {
+		slept = append(slept, d)
+		return nil
+	}
+
+	srv.Inject(todoisttest.Fault{Status: http.StatusTooManyRequests, RetryAfter: 2 * time.Second})
+	if _, err := store.List(context.Background(), nil); err != nil {
+		t.Fatalf("expected the retry to succeed, got %v", err)
+	}
+	if len(slept) != 1 || slept[0] != 2*time.Second {
+		t.Errorf("expected to wait the Retry-After of 2s, waited %v", slept)
+	}
+
+	// Waits longer than MaxDelay are not worth blocking on.
+	srv.Inject(todoisttest.Fault{Status: http.StatusTooManyRequests, RetryAfter: time.Hour})
+	_, err := store.List(context.Background(), nil)
+	if !errors.Is(err, ErrRateLimited) {
+		t.Errorf("expected ErrRateLimited, got %v", err)
+	}
+	if len(slept) != 1 {
+		t.Errorf("expected no further waits, waited %v", slept)
+	}
+}
+
+func TestStore_NoRetryOnClientErrors(t *testing.T) {
+	store, srv := newTestStore(t)
+
+	srv.Inject(todoisttest.Fault{Status: http.StatusBadRequest})
+	if _, err := store.List(context.Background(), nil); err == nil {
+		t.Fatal("expected an error")
+	}
+	if n := len(srv.Requests()); n != 1 {
+		t.Errorf("expected a single attempt, got %d", n)
+	}
+}
+
+func TestStore_IdempotentCreate(t *testing.T) {
+	store, srv := newTestStore(t)
+
+	// The task is created but the response is lost.
+	srv.Inject(todoisttest.Fault{Method: http.MethodPost, Path: "/tasks", Status: http.StatusBadGateway, AfterServe: true})
+
+	r := protocol.NewReminder("Only once")
+	if err := store.Add(context.Background(), r); err != nil {
+		t.Fatalf("failed to add: %v", err)
+	}
+
+	if n := len(srv.Tasks()); n != 1 {
+		t.Errorf("expected 1 task after retrying the create, got %d", n)
+	}
+	requests := srv.Requests()
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 attempts, got %d", len(requests))
+	}
+	first, second := requests[0].Header.Get("X-Request-Id"), requests[1].Header.Get("X-Request-Id")
+	if first == "" || first != second {
+		t.Errorf("expected the same X-Request-Id on both attempts, got %q and %q", first, second)
+	}
+}
+
+func TestStore_Unauthorized(t *testing.T) {
+	srv := todoisttest.NewServer()
+	defer srv.Close()
+	store := New("wrong-token", "", WithBaseURL(srv.URL))
+
+	_, err := store.List(context.Background(), nil)
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized, got %v", err)
+	}
+	if n := len(srv.Requests()); n != 1 {
+		t.Errorf("expected a single attempt, got %d", n)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	for attempt, full := range []time.Duration{100, 200, 400, 800, 1000, 1000} {
+		full *= time.Millisecond
+		if full > p.MaxDelay {
+			full = p.MaxDelay
+		}
+		for i := 0; i < 20; i++ {
+			d := backoff(p, attempt+1)
+			if d < full/2 || d > full {
+				t.Fatalf("attempt %d: delay %s outside [%s, %s]", attempt+1, d, full/2, full)
+			}
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	if got := parseRetryAfter("5", now); got != 5*time.Second {
+		t.Errorf("expected 5s, got %s", got)
+	}
+	if got := parseRetryAfter("Thu, 01 Jan 2026 12:00:30 GMT", now); got != 30*time.Second {
+		t.Errorf("expected 30s, got %s", got)
+	}
+	if got := parseRetryAfter("soon", now); got != 0 {
+		t.Errorf("expected 0 for junk, got %s", got)
+	}
+}
+
+func TestStore_CompleteRecurring(t *testing.T) {
+	store, srv := newTestStore(t)
+	ctx := context.Background()
+
+	r := protocol.NewReminder("Standup notes")
+	r.SetDue(time.Date(2026, 3, 16, 9, 0, 0, 0, time.Local))
+	rec, _ := protocol.ParseRecurrence("every weekday")
+	r.SetRecurrence(rec)
+	if err := store.Add(ctx, r); err != nil {
+		t.Fatalf("failed to add: %v", err)
+	}
+	if task, _ := srv.Task(r.ID); task.Due == nil || !task.Due.IsRecurring {
+		t.Fatalf("expected a recurring due date, got %+v", task.Due)
+	}
+
+	if err := store.Complete(ctx, r.ID); err != nil {
+		t.Fatalf("failed to complete: %v", err)
+	}
+
+	got, err := store.Get(ctx, r.ID)
+	if err != nil {
+		t.Fatalf("failed to get: %v", err)
+	}
+	if want := time.Date(2026, 3, 17, 9, 0, 0, 0, time.Local); got.Due == nil || !got.Due.Equal(want) {
+		t.Errorf("expected due to advance to %s, got %v", want, got.Due)
+	}
+	if got.Recurrence == nil || got.Recurrence.String() != rec.String() {
+		t.Errorf("expected recurrence %s, got %v", rec, got.Recurrence)
+	}
+}
+
+func TestStore_RecurrenceRoundTrip(t *testing.T) {
+	store, _ := newTestStore(t)
+	ctx := context.Background()
+
+	for _, rule := range []string{
+		"FREQ=DAILY",
+		"FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR",
+		"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE",
+		"FREQ=MONTHLY;INTERVAL=3",
+		"FREQ=DAILY;COUNT=5",
+		"FREQ=YEARLY;UNTIL=20301231",
+	} {
+		rec, _ := protocol.ParseRRule(rule)
+		r := protocol.NewReminder("Water plants")
+		r.SetDue(time.Date(2026, 3, 16, 9, 0, 0, 0, time.Local))
+		r.SetRecurrence(rec)
+		if err := store.Add(ctx, r); err != nil {
+			t.Fatalf("%s: failed to add: %v", rule, err)
+		}
+
+		got, err := store.Get(ctx, r.ID)
+		if err != nil {
+			t.Fatalf("%s: failed to get: %v", rule, err)
+		}
+		if got.Recurrence == nil || got.Recurrence.String() != rec.String() {
+			t.Errorf("%s: got recurrence %v", rule, got.Recurrence)
+		}
+	}
+}
+
+func TestStore_CompleteRecurringCount(t *testing.T) {
+	store, _ := newTestStore(t)
+	ctx := context.Background()
+
+	r := protocol.NewReminder("Take antibiotics")
+	r.SetDue(time.Date(2026, 3, 16, 9, 0, 0, 0, time.Local))
+	r.SetRecurrence(&protocol.Recurrence{Freq: protocol.Daily, Count: 2})
+	if err := store.Add(ctx, r); err != nil {
+		t.Fatalf("failed to add: %v", err)
+	}
+
+	if err := store.Complete(ctx, r.ID); err != nil {
+		t.Fatalf("failed to complete: %v", err)
+	}
+	got, err := store.Get(ctx, r.ID)
+	if err != nil {
+		t.Fatalf("failed to get: %v", err)
+	}
+	if got.Completed || got.Recurrence == nil || got.Recurrence.Count != 1 {
+		t.Fatalf("expected one occurrence left, got completed=%v recurrence=%v", got.Completed, got.Recurrence)
+	}
+
+	if err := store.Complete(ctx, r.ID); err != nil {
+		t.Fatalf("failed to complete: %v", err)
+	}
+	if got, err := store.Get(ctx, r.ID); err != nil || !got.Completed {
+		t.Errorf("expected the last occurrence to close the reminder, got %+v, %v", got, err)
+	}
+}
+
+func TestStore_ListPaginates(t *testing.T) {
+	srv := todoisttest.NewServer()
+	defer srv.Close()
+	store := New(srv.Token, "", WithBaseURL(srv.URL), WithPageSize(2))
+
+	for i := 0; i < 5; i++ {
+		srv.AddTask(todoisttest.Task{Content: fmt.Sprintf("Task %d", i)})
+	}
+
+	reminders, err := store.List(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("failed to list: %v", err)
+	}
+	if len(reminders) != 5 {
+		t.Errorf("expected all 5 tasks across pages, got %d", len(reminders))
+	}
+
+	requests := srv.Requests()
+	if len(requests) != 3 {
+		t.Fatalf("expected 3 page requests, got %d", len(requests))
+	}
+	if got := requests[0].Query.Get("limit"); got != "2" {
+		t.Errorf("expected limit=2, got %q", got)
+	}
+	if requests[0].Query.Get("cursor") != "" || requests[1].Query.Get("cursor") == "" {
+		t.Error("expected the cursor to be sent from the second page on")
+	}
+}
+
+func TestStore_ListMaxPages(t *testing.T) {
+	srv := todoisttest.NewServer()
+	defer srv.Close()
+	store := New(srv.Token, "", WithBaseURL(srv.URL), WithPageSize(2), WithMaxPages(2))
+
+	for i := 0; i < 5; i++ {
+		srv.AddTask(todoisttest.Task{Content: fmt.Sprintf("Task %d", i)})
+	}
+
+	if _, err := store.List(context.Background(), nil); err == nil {
+		t.Error("expected an error when the page cap is reached")
+	}
+}
+
+func TestStore_ListEachStops(t *testing.T) {
+	srv := todoisttest.NewServer()
+	defer srv.Close()
+	store := New(srv.Token, "", WithBaseURL(srv.URL), WithPageSize(2))
+
+	for i := 0; i < 5; i++ {
+		srv.AddTask(todoisttest.Task{Content: fmt.Sprintf("Task %d", i)})
+	}
+
+	stop := errors.New("stop")
+	var seen int
+	err := store.ListEach(context.Background(), nil, func(r *protocol.Reminder) error {
+		seen++
+		return stop
+	})
+	if !errors.Is(err, stop) {
+		t.Errorf("expected the callback error, got %v", err)
+	}
+	if seen != 1 || len(srv.Requests()) != 1 {
+		t.Errorf("expected to stop after the first task and page, saw %d tasks and %d requests", seen, len(srv.Requests()))
+	}
+}
+
+func TestStore_ProjectScope(t *testing.T) {
+	srv := todoisttest.NewServer()
+	defer srv.Close()
+	project := srv.AddProject("Recall")
+	srv.AddTask(todoisttest.Task{Content: "Inbox task"})
+	store := New(srv.Token, "recall", WithBaseURL(srv.URL))
+	ctx := context.Background()
+
+	r := protocol.NewReminder("Scoped task")
+	if err := store.Add(ctx, r); err != nil {
+		t.Fatalf("failed to add: %v", err)
+	}
+	if task, _ := srv.Task(r.ID); task.ProjectID != project.ID {
+		t.Errorf("expected task in project %s, got %s", project.ID, task.ProjectID)
+	}
+
+	reminders, err := store.List(ctx, nil)
+	if err != nil {
+		t.Fatalf("failed to list: %v", err)
+	}
+	if len(reminders) != 1 || reminders[0].Title != "Scoped task" {
+		t.Errorf("expected only the project's task, got %v", reminders)
+	}
+
+	var lookups int
+	for _, req := range srv.Requests() {
+		if req.Method == http.MethodGet && req.Path == "/projects" {
+			lookups++
+		}
+	}
+	if lookups != 1 {
+		t.Errorf("expected the project to be looked up once, got %d lookups", lookups)
+	}
+}
+
+func TestStore_CreatesProjectAndSection(t *testing.T) {
+	srv := todoisttest.NewServer()
+	defer srv.Close()
+	store := New(srv.Token, "Recall", WithBaseURL(srv.URL), WithSection("Agents"))
+
+	r := protocol.NewReminder("Captured")
+	if err := store.Add(context.Background(), r); err != nil {
+		t.Fatalf("failed to add: %v", err)
+	}
+
+	projects, sections := srv.Projects(), srv.Sections()
+	if len(projects) != 2 || projects[1].Name != "Recall" {
+		t.Fatalf("expected Recall project to be created, got %v", projects)
+	}
+	if len(sections) != 1 || sections[0].Name != "Agents" || sections[0].ProjectID != projects[1].ID {
+		t.Fatalf("expected Agents section in Recall, got %v", sections)
+	}
+
+	task, _ := srv.Task(r.ID)
+	if task.SectionID == nil || *task.SectionID != sections[0].ID {
+		t.Errorf("expected task in section %s, got %v", sections[0].ID, task.SectionID)
+	}
+}
+
+func TestStore_ListMissingScope(t *testing.T) {
+	srv := todoisttest.NewServer()
+	defer srv.Close()
+	srv.AddProject("Recall")
+	ctx := context.Background()
+
+	for _, store := range []*Store{
+		New(srv.Token, "Someday", WithBaseURL(srv.URL)),
+		New(srv.Token, "Recall", WithBaseURL(srv.URL), WithSection("Agents")),
+	} {
+		reminders, err := store.List(ctx, &protocol.ListFilter{IncludeCompleted: true})
+		if err != nil {
+			t.Fatalf("failed to list: %v", err)
+		}
+		if len(reminders) != 0 {
+			t.Errorf("expected no reminders, got %v", reminders)
+		}
+	}
+	if projects, sections := srv.Projects(), srv.Sections(); len(projects) != 2 || len(sections) != 0 {
+		t.Errorf("expected listing to create nothing, got %v and %v", projects, sections)
+	}
+
+	// Adding afterwards still creates the scope.
+	store := New(srv.Token, "Someday", WithBaseURL(srv.URL))
+	if _, err := store.List(ctx, nil); err != nil {
+		t.Fatalf("failed to list: %v", err)
+	}
+	r := protocol.NewReminder("Learn piano")
+	if err := store.Add(ctx, r); err != nil {
+		t.Fatalf("failed to add: %v", err)
+	}
+	reminders, err := store.List(ctx, nil)
+	if err != nil {
+		t.Fatalf("failed to list: %v", err)
+	}
+	if len(reminders) != 1 || reminders[0].Title != "Learn piano" {
+		t.Errorf("expected the new reminder to be listed, got %v", reminders)
+	}
+}
+
+func TestStore_LabelFilter(t *testing.T) {
+	store, srv := newTestStore(t)
+	srv.AddTask(todoisttest.Task{Content: "Work task", Labels: []string{"Work"}})
+	srv.AddTask(todoisttest.Task{Content: "Home task", Labels: []string{"home"}})
+
+	reminders, err := store.List(context.Background(), &protocol.ListFilter{Tags: []string{"work"}})
+	if err != nil {
+		t.Fatalf("failed to list: %v", err)
+	}
+	if len(reminders) != 1 || reminders[0].Title != "Work task" {
+		t.Errorf("expected only the work task, got %v", reminders)
+	}
+
+	requests := srv.Requests()
+	if got := requests[len(requests)-1].Query.Get("label"); got != "Work" {
+		t.Errorf("expected the label to be filtered server-side as %q, got %q", "Work", got)
+	}
+}
+
+func TestStore_ListCompleted(t *testing.T) {
+	store, srv := newTestStore(t)
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+	store.completedWindow = 180 * 24 * time.Hour
+
+	completedAt := func(daysAgo int) *string {
+		ts := now.AddDate(0, 0, -daysAgo).Format(time.RFC3339)
+		return &ts
+	}
+	srv.AddTask(todoisttest.Task{Content: "Open"})
+	srv.AddTask(todoisttest.Task{Content: "Done last week", Checked: true, CompletedAt: completedAt(7)})
+	srv.AddTask(todoisttest.Task{Content: "Done in winter", Checked: true, CompletedAt: completedAt(120)})
+	srv.AddTask(todoisttest.Task{Content: "Done last year", Checked: true, CompletedAt: completedAt(365)})
+
+	reminders, err := store.List(context.Background(), &protocol.ListFilter{})
+	if err != nil {
+		t.Fatalf("failed to list: %v", err)
+	}
+	if len(reminders) != 1 {
+		t.Fatalf("expected only the open task without IncludeCompleted, got %d", len(reminders))
+	}
+
+	reminders, err = store.List(context.Background(), &protocol.ListFilter{IncludeCompleted: true})
+	if err != nil {
+		t.Fatalf("failed to list: %v", err)
+	}
+	var titles []string
+	for _, r := range reminders {
+		titles = append(titles, r.Title)
+		if r.Title != "Open" && (!r.Completed || r.CompletedAt == nil) {
+			t.Errorf("expected %q to be completed with CompletedAt set", r.Title)
+		}
+	}
+	if want := []string{"Open", "Done last week", "Done in winter"}; !slices.Equal(titles, want) {
+		t.Errorf("expected %v, got %v", want, titles)
+	}
+
+	// A 180-day window takes three requests of at most twelve weeks.
+	var ranges int
+	for _, req := range srv.Requests() {
+		if req.Path == "/tasks/completed/by_completion_date" {
+			ranges++
+		}
+	}
+	if ranges != 3 {
+		t.Errorf("expected 3 completed-task requests, got %d", ranges)
+	}
+}
+
+func TestStore_Conformance(t *testing.T) {
+	storetest.RunConformance(t, func(t *testing.T) protocol.Store {
+		store, _ := newTestStore(t)
+		return store
+	})
+}
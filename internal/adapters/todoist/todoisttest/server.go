@@ -0,0 +1,1075 @@
+// Package todoisttest provides an in-process fake of the Todoist v1 REST
+// API for tests. It covers the endpoints the todoist adapter uses: task
+// CRUD, closing, reopening and moving, completed tasks, projects,
+// sections, labels and cursor pagination. Faults such as 429s and 5xxs
+// can be injected per endpoint, and POSTs are deduplicated by
+// X-Request-Id like the real API.
+//
+//	srv := todoisttest.NewServer()
+//	defer srv.Close()
+//	store := todoist.New(srv.Token, "", todoist.WithBaseURL(srv.URL))
+package todoisttest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shaneoxm/recall/internal/protocol"
+)
+
+// InboxProjectID is the project tasks land in when none is given.
+const InboxProjectID = "inbox"
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+	timestampLayout = "2006-01-02T15:04:05.000000Z"
+)
+
+// Task is a task as the fake stores and returns it, in the v1 wire format.
+type Task struct {
+	ID          string   `json:"id"`
+	ProjectID   string   `json:"project_id"`
+	SectionID   *string  `json:"section_id"`
+	ParentID    *string  `json:"parent_id"`
+	Content     string   `json:"content"`
+	Description string   `json:"description"`
+	Labels      []string `json:"labels"`
+	Priority    int      `json:"priority"`
+	Due         *Due     `json:"due"`
+	Checked     bool     `json:"checked"`
+	IsDeleted   bool     `json:"is_deleted"`
+	ChildOrder  int      `json:"child_order"`
+	AddedAt     string   `json:"added_at"`
+	UpdatedAt   string   `json:"updated_at"`
+	CompletedAt *string  `json:"completed_at"`
+}
+
+// Due is a task's due date. Date is "2006-01-02" for all-day tasks and
+// "2006-01-02T15:04:05" for floating times.
+type Due struct {
+	Date        string  `json:"date"`
+	Timezone    *string `json:"timezone"`
+	String      string  `json:"string"`
+	Lang        string  `json:"lang"`
+	IsRecurring bool    `json:"is_recurring"`
+}
+
+// Project is a Todoist project.
+type Project struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	InboxProject bool   `json:"inbox_project"`
+}
+
+// Section is a section within a project.
+type Section struct {
+	ID        string `json:"id"`
+	ProjectID string `json:"project_id"`
+	Name      string `json:"name"`
+}
+
+// Label is a personal label.
+type Label struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+	Order int    `json:"order"`
+}
+
+// Fault makes matching requests fail instead of being served.
+type Fault struct {
+	// Method and Path select requests; empty matches any. Path is matched
+	// as a prefix of the path below the API root, e.g. "/tasks".
+	Method string
+	Path   string
+
+	// Status is the HTTP status to return.
+	Status int
+
+	// RetryAfter, if set, is sent as a Retry-After header in seconds.
+	RetryAfter time.Duration
+
+	// Times is how many requests fail before the fault clears. Zero means
+	// one.
+	Times int
+
+	// AfterServe handles the request normally and then returns the
+	// failure instead of the response, as when a response is lost on the
+	// way back.
+	AfterServe bool
+}
+
+// Request is a request the fake received.
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+// Server is a fake Todoist API backed by an httptest.Server.
+type Server struct {
+	// URL is the API root to pass to todoist.WithBaseURL.
+	URL string
+
+	// Token is the bearer token the fake accepts.
+	Token string
+
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+
+	srv *httptest.Server
+
+	mu       sync.Mutex
+	tasks    []*Task
+	projects []*Project
+	sections []*Section
+	labels   []*Label
+	nextID   int
+	faults   []*Fault
+	requests []Request
+
+	// replies holds responses to POSTs by X-Request-Id, so a retried
+	// request is answered without being applied twice.
+	replies map[string]*recorder
+}
+
+// recorder captures a response so it can be replayed.
+type recorder struct {
+	header http.Header
+	status int
+	body   strings.Builder
+}
+
+func (r *recorder) Header() http.Header         { return r.header }
+func (r *recorder) Write(b []byte) (int, error) { return r.body.WriteString(string(b)) }
+func (r *recorder) WriteHeader(status int)      { r.status = status }
+
+func (r *recorder) replay(w http.ResponseWriter) {
+	for k, v := range r.header {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(r.status)
+	io.WriteString(w, r.body.String())
+}
+
+// NewServer starts a fake Todoist API. Call Close when done.
+func NewServer() *Server {
+	s := &Server{
+		Token:    "test-token",
+		nextID:   1000,
+		replies:  map[string]*recorder{},
+		projects: []*Project{{ID: InboxProjectID, Name: "Inbox", InboxProject: true}},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /tasks", s.listTasks)
+	mux.HandleFunc("POST /tasks", s.createTask)
+	mux.HandleFunc("GET /tasks/completed/by_completion_date", s.listCompleted)
+	mux.HandleFunc("GET /tasks/{id}", s.getTask)
+	mux.HandleFunc("POST /tasks/{id}", s.updateTask)
+	mux.HandleFunc("DELETE /tasks/{id}", s.deleteTask)
+	mux.HandleFunc("POST /tasks/{id}/close", s.closeTask)
+	mux.HandleFunc("POST /tasks/{id}/reopen", s.reopenTask)
+	mux.HandleFunc("POST /tasks/{id}/move", s.moveTask)
+	mux.HandleFunc("GET /projects", s.listProjects)
+	mux.HandleFunc("POST /projects", s.createProject)
+	mux.HandleFunc("GET /sections", s.listSections)
+	mux.HandleFunc("POST /sections", s.createSection)
+	mux.HandleFunc("GET /labels", s.listLabels)
+
+	s.srv = httptest.NewServer(http.StripPrefix("/api/v1", s.middleware(mux)))
+	s.URL = s.srv.URL + "/api/v1"
+	return s
+}
+
+// Close shuts the server down.
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Client returns an HTTP client for the server.
+func (s *Server) Client() *http.Client {
+	return s.srv.Client()
+}
+
+// Inject adds a fault. Faults are checked in the order they were added.
+func (s *Server) Inject(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if f.Times == 0 {
+		f.Times = 1
+	}
+	s.faults = append(s.faults, &f)
+}
+
+// Requests returns the requests received so far, including failed ones.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.requests)
+}
+
+// Tasks returns copies of all tasks, open and closed.
+func (s *Server) Tasks() []Task {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]Task, 0, len(s.tasks))
+	for _, t := range s.tasks {
+		out = append(out, *t)
+	}
+	return out
+}
+
+// Task returns a copy of the task with the given ID.
+func (s *Server) Task(id string) (Task, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if t := s.find(id); t != nil {
+		return *t, true
+	}
+	return Task{}, false
+}
+
+// AddTask stores a task directly, filling in an ID, project and
+// timestamps when they are empty. It returns the stored copy.
+func (s *Server) AddTask(t Task) Task {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if t.ID == "" {
+		t.ID = s.newID()
+	}
+	if t.ProjectID == "" {
+		t.ProjectID = InboxProjectID
+	}
+	if t.Priority == 0 {
+		t.Priority = 1
+	}
+	if t.AddedAt == "" {
+		t.AddedAt = s.timestamp()
+	}
+	if t.UpdatedAt == "" {
+		t.UpdatedAt = t.AddedAt
+	}
+	if t.Labels == nil {
+		t.Labels = []string{}
+	}
+	s.registerLabels(t.Labels)
+	s.tasks = append(s.tasks, &t)
+	return t
+}
+
+// AddProject creates a project and returns it.
+func (s *Server) AddProject(name string) Project {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := &Project{ID: s.newID(), Name: name}
+	s.projects = append(s.projects, p)
+	return *p
+}
+
+// AddSection creates a section in a project and returns it.
+func (s *Server) AddSection(projectID, name string) Section {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sec := &Section{ID: s.newID(), ProjectID: projectID, Name: name}
+	s.sections = append(s.sections, sec)
+	return *sec
+}
+
+// Projects returns copies of all projects, starting with the Inbox.
+func (s *Server) Projects() []Project {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]Project, 0, len(s.projects))
+	for _, p := range s.projects {
+		out = append(out, *p)
+	}
+	return out
+}
+
+// Sections returns copies of all sections.
+func (s *Server) Sections() []Section {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]Section, 0, len(s.sections))
+	for _, sec := range s.sections {
+		out = append(out, *sec)
+	}
+	return out
+}
+
+// AddLabel creates a personal label.
+func (s *Server) AddLabel(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.registerLabels([]string{name})
+}
+
+// Labels returns the names of all labels.
+func (s *Server) Labels() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var names []string
+	for _, l := range s.labels {
+		names = append(names, l.Name)
+	}
+	return names
+}
+
+func (s *Server) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(strings.NewReader(string(body)))
+
+		s.mu.Lock()
+		s.requests = append(s.requests, Request{
+			Method: r.Method,
+			Path:   r.URL.Path,
+			Query:  r.URL.Query(),
+			Header: r.Header.Clone(),
+			Body:   body,
+		})
+		fault := s.takeFault(r)
+		s.mu.Unlock()
+
+		if fault != nil && !fault.AfterServe {
+			fail(w, fault)
+			return
+		}
+
+		if r.Header.Get("Authorization") != "Bearer "+s.Token {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		rec := s.serve(next, r)
+		if fault != nil {
+			fail(w, fault)
+			return
+		}
+		rec.replay(w)
+	})
+}
+
+// serve handles r, or finds the earlier response to a POST with the same
+// X-Request-Id.
+func (s *Server) serve(next http.Handler, r *http.Request) *recorder {
+	id := r.Header.Get("X-Request-Id")
+	if r.Method == http.MethodPost && id != "" {
+		s.mu.Lock()
+		prev, ok := s.replies[id]
+		s.mu.Unlock()
+		if ok {
+			return prev
+		}
+	}
+
+	rec := &recorder{header: http.Header{}, status: http.StatusOK}
+	next.ServeHTTP(rec, r)
+
+	if r.Method == http.MethodPost && id != "" && rec.status < 400 {
+		s.mu.Lock()
+		s.replies[id] = rec
+		s.mu.Unlock()
+	}
+	return rec
+}
+
+func fail(w http.ResponseWriter, f *Fault) {
+	if f.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(f.RetryAfter.Seconds())))
+	}
+	http.Error(w, http.StatusText(f.Status), f.Status)
+}
+
+func (s *Server) takeFault(r *http.Request) *Fault {
+	for i, f := range s.faults {
+		if f.Method != "" && f.Method != r.Method {
+			continue
+		}
+		if !strings.HasPrefix(r.URL.Path, f.Path) {
+			continue
+		}
+		f.Times--
+		if f.Times <= 0 {
+			s.faults = append(s.faults[:i], s.faults[i+1:]...)
+		}
+		return f
+	}
+	return nil
+}
+
+// page is the v1 paginated list envelope.
+type page[T any] struct {
+	Results    []T     `json:"results"`
+	NextCursor *string `json:"next_cursor"`
+}
+
+func (s *Server) listTasks(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q := r.URL.Query()
+	var matched []Task
+	for _, t := range s.tasks {
+		if t.Checked || t.IsDeleted {
+			continue
+		}
+		if v := q.Get("project_id"); v != "" && t.ProjectID != v {
+			continue
+		}
+		if v := q.Get("section_id"); v != "" && (t.SectionID == nil || *t.SectionID != v) {
+			continue
+		}
+		if v := q.Get("parent_id"); v != "" && (t.ParentID == nil || *t.ParentID != v) {
+			continue
+		}
+		if v := q.Get("label"); v != "" && !slices.Contains(t.Labels, v) {
+			continue
+		}
+		matched = append(matched, *t)
+	}
+
+	results, next, err := paginate(matched, q)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, page[Task]{Results: results, NextCursor: next})
+}
+
+// listCompleted serves tasks completed between since and until, most
+// recently completed first. Like the real API it rejects ranges longer
+// than three months.
+func (s *Server) listCompleted(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	since, err1 := time.Parse(time.RFC3339, q.Get("since"))
+	until, err2 := time.Parse(time.RFC3339, q.Get("until"))
+	if err1 != nil || err2 != nil {
+		http.Error(w, "since and until are required", http.StatusBadRequest)
+		return
+	}
+	if until.Before(since) || since.AddDate(0, 3, 0).Before(until) {
+		http.Error(w, "range must be at most three months", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var matched []Task
+	for _, t := range s.tasks {
+		if !t.Checked || t.IsDeleted || t.CompletedAt == nil {
+			continue
+		}
+		done, err := time.Parse(time.RFC3339, *t.CompletedAt)
+		if err != nil || done.Before(since) || done.After(until) {
+			continue
+		}
+		if v := q.Get("project_id"); v != "" && t.ProjectID != v {
+			continue
+		}
+		if v := q.Get("section_id"); v != "" && (t.SectionID == nil || *t.SectionID != v) {
+			continue
+		}
+		matched = append(matched, *t)
+	}
+	slices.SortStableFunc(matched, func(a, b Task) int {
+		return strings.Compare(*b.CompletedAt, *a.CompletedAt)
+	})
+
+	results, next, err := paginate(matched, q)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, struct {
+		Items      []Task  `json:"items"`
+		NextCursor *string `json:"next_cursor"`
+	}{results, next})
+}
+
+func (s *Server) listProjects(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var all []Project
+	for _, p := range s.projects {
+		all = append(all, *p)
+	}
+
+	results, next, err := paginate(all, r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, page[Project]{Results: results, NextCursor: next})
+}
+
+func (s *Server) createProject(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || strings.TrimSpace(req.Name) == "" {
+		http.Error(w, "name is required", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := &Project{ID: s.newID(), Name: req.Name}
+	s.projects = append(s.projects, p)
+	writeJSON(w, p)
+}
+
+func (s *Server) listSections(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	projectID := r.URL.Query().Get("project_id")
+	var matched []Section
+	for _, sec := range s.sections {
+		if projectID == "" || sec.ProjectID == projectID {
+			matched = append(matched, *sec)
+		}
+	}
+
+	results, next, err := paginate(matched, r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, page[Section]{Results: results, NextCursor: next})
+}
+
+func (s *Server) createSection(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name      string `json:"name"`
+		ProjectID string `json:"project_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || strings.TrimSpace(req.Name) == "" {
+		http.Error(w, "name is required", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.findProject(req.ProjectID) == nil {
+		http.Error(w, "project not found", http.StatusBadRequest)
+		return
+	}
+
+	sec := &Section{ID: s.newID(), ProjectID: req.ProjectID, Name: req.Name}
+	s.sections = append(s.sections, sec)
+	writeJSON(w, sec)
+}
+
+func (s *Server) listLabels(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var all []Label
+	for _, l := range s.labels {
+		all = append(all, *l)
+	}
+
+	results, next, err := paginate(all, r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, page[Label]{Results: results, NextCursor: next})
+}
+
+// paginate slices items according to the limit and cursor parameters.
+// Cursors are opaque to clients; here they are offsets.
+func paginate[T any](items []T, q url.Values) ([]T, *string, error) {
+	limit := defaultPageSize
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxPageSize {
+			return nil, nil, fmt.Errorf("limit must be between 1 and %d", maxPageSize)
+		}
+		limit = n
+	}
+
+	offset := 0
+	if v := q.Get("cursor"); v != "" {
+		n, err := strconv.Atoi(strings.TrimPrefix(v, "c"))
+		if err != nil || !strings.HasPrefix(v, "c") || n < 0 {
+			return nil, nil, fmt.Errorf("invalid cursor")
+		}
+		offset = n
+	}
+
+	results := []T{}
+	if offset < len(items) {
+		results = items[offset:min(offset+limit, len(items))]
+	}
+
+	var next *string
+	if offset+limit < len(items) {
+		c := "c" + strconv.Itoa(offset+limit)
+		next = &c
+	}
+	return results, next, nil
+}
+
+// taskRequest is the body of create and update requests.
+type taskRequest struct {
+	Content     *string   `json:"content"`
+	Description *string   `json:"description"`
+	DueString   *string   `json:"due_string"`
+	DueDate     *string   `json:"due_date"`
+	DueDatetime *string   `json:"due_datetime"`
+	Priority    *int      `json:"priority"`
+	Labels      *[]string `json:"labels"`
+	ParentID    *string   `json:"parent_id"`
+	ProjectID   *string   `json:"project_id"`
+	SectionID   *string   `json:"section_id"`
+}
+
+func (s *Server) createTask(w http.ResponseWriter, r *http.Request) {
+	var req taskRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Content == nil || strings.TrimSpace(*req.Content) == "" {
+		http.Error(w, "content is required", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.timestamp()
+	t := &Task{
+		ID:        s.newID(),
+		ProjectID: InboxProjectID,
+		Labels:    []string{},
+		Priority:  1,
+		AddedAt:   now,
+		UpdatedAt: now,
+	}
+	if req.ParentID != nil {
+		parent := s.find(*req.ParentID)
+		if parent == nil {
+			http.Error(w, "parent task not found", http.StatusBadRequest)
+			return
+		}
+		t.ProjectID = parent.ProjectID
+	}
+	if err := s.apply(t, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.tasks = append(s.tasks, t)
+	writeJSON(w, t)
+}
+
+func (s *Server) getTask(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t := s.find(r.PathValue("id"))
+	if t == nil {
+		http.Error(w, "Task not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, t)
+}
+
+func (s *Server) updateTask(w http.ResponseWriter, r *http.Request) {
+	var req taskRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t := s.find(r.PathValue("id"))
+	if t == nil {
+		http.Error(w, "Task not found", http.StatusNotFound)
+		return
+	}
+	// Moving tasks goes through a separate endpoint in v1.
+	req.ParentID, req.ProjectID, req.SectionID = nil, nil, nil
+
+	updated := *t
+	if err := s.apply(&updated, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	updated.UpdatedAt = s.timestamp()
+	*t = updated
+	writeJSON(w, t)
+}
+
+func (s *Server) deleteTask(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t := s.find(r.PathValue("id"))
+	if t == nil {
+		http.Error(w, "Task not found", http.StatusNotFound)
+		return
+	}
+
+	// Deleting a task deletes its subtasks too.
+	s.tasks = slices.DeleteFunc(s.tasks, func(x *Task) bool {
+		return x == t || (x.ParentID != nil && *x.ParentID == t.ID)
+	})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) closeTask(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t := s.find(r.PathValue("id"))
+	if t == nil {
+		http.Error(w, "Task not found", http.StatusNotFound)
+		return
+	}
+
+	now := s.timestamp()
+	t.UpdatedAt = now
+	if t.Due != nil && t.Due.IsRecurring && advance(t.Due) {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	t.Checked = true
+	t.CompletedAt = &now
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) reopenTask(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t := s.find(r.PathValue("id"))
+	if t == nil {
+		http.Error(w, "Task not found", http.StatusNotFound)
+		return
+	}
+	t.Checked = false
+	t.CompletedAt = nil
+	t.UpdatedAt = s.timestamp()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// moveTask handles a move to a parent task, section or project; exactly
+// one of them must be given. Subtasks move along with the task.
+func (s *Server) moveTask(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ParentID  *string `json:"parent_id"`
+		SectionID *string `json:"section_id"`
+		ProjectID *string `json:"project_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t := s.find(r.PathValue("id"))
+	if t == nil {
+		http.Error(w, "Task not found", http.StatusNotFound)
+		return
+	}
+
+	switch {
+	case req.ParentID != nil && req.SectionID == nil && req.ProjectID == nil:
+		parent := s.find(*req.ParentID)
+		if parent == nil || parent == t {
+			http.Error(w, "parent task not found", http.StatusBadRequest)
+			return
+		}
+		t.ParentID = &parent.ID
+		t.ProjectID, t.SectionID = parent.ProjectID, parent.SectionID
+	case req.SectionID != nil && req.ParentID == nil && req.ProjectID == nil:
+		sec := s.findSection(*req.SectionID)
+		if sec == nil {
+			http.Error(w, "section not found", http.StatusBadRequest)
+			return
+		}
+		t.ParentID = nil
+		t.ProjectID, t.SectionID = sec.ProjectID, &sec.ID
+	case req.ProjectID != nil && req.ParentID == nil && req.SectionID == nil:
+		if s.findProject(*req.ProjectID) == nil {
+			http.Error(w, "project not found", http.StatusBadRequest)
+			return
+		}
+		t.ParentID, t.SectionID = nil, nil
+		t.ProjectID = *req.ProjectID
+	default:
+		http.Error(w, "exactly one of parent_id, section_id or project_id is required", http.StatusBadRequest)
+		return
+	}
+
+	t.UpdatedAt = s.timestamp()
+	for _, child := range s.tasks {
+		if child.ParentID != nil && *child.ParentID == t.ID {
+			child.ProjectID, child.SectionID = t.ProjectID, t.SectionID
+		}
+	}
+	writeJSON(w, t)
+}
+
+// apply copies the fields set in req onto t.
+func (s *Server) apply(t *Task, req *taskRequest) error {
+	if req.Content != nil {
+		t.Content = *req.Content
+	}
+	if req.Description != nil {
+		t.Description = *req.Description
+	}
+	if req.Priority != nil {
+		if *req.Priority < 1 || *req.Priority > 4 {
+			return fmt.Errorf("priority must be between 1 and 4")
+		}
+		t.Priority = *req.Priority
+	}
+	if req.Labels != nil {
+		t.Labels = slices.Clone(*req.Labels)
+		s.registerLabels(t.Labels)
+	}
+	if req.ParentID != nil {
+		t.ParentID = req.ParentID
+	}
+	if req.ProjectID != nil {
+		if s.findProject(*req.ProjectID) == nil {
+			return fmt.Errorf("project %s not found", *req.ProjectID)
+		}
+		t.ProjectID = *req.ProjectID
+	}
+	if req.SectionID != nil {
+		sec := s.findSection(*req.SectionID)
+		if sec == nil {
+			return fmt.Errorf("section %s not found", *req.SectionID)
+		}
+		t.SectionID = req.SectionID
+		t.ProjectID = sec.ProjectID
+	}
+
+	switch {
+	case req.DueString != nil:
+		due, err := parseDueString(*req.DueString, s.now())
+		if err != nil {
+			return err
+		}
+		t.Due = due
+	case req.DueDatetime != nil:
+		d, err := time.Parse(time.RFC3339, *req.DueDatetime)
+		if err != nil {
+			return fmt.Errorf("invalid due_datetime: %w", err)
+		}
+		utc := "UTC"
+		t.Due = &Due{Date: d.UTC().Format("2006-01-02T15:04:05Z"), Timezone: &utc, String: *req.DueDatetime, Lang: "en"}
+	case req.DueDate != nil:
+		if _, err := time.Parse("2006-01-02", *req.DueDate); err != nil {
+			return fmt.Errorf("invalid due_date: %w", err)
+		}
+		t.Due = &Due{Date: *req.DueDate, String: *req.DueDate, Lang: "en"}
+	}
+	return nil
+}
+
+// parseDueString understands the due strings the adapter sends: dates,
+// dates with a time, and recurring "every ..." rules with optional
+// "for N times", "at HH:MM", "starting YYYY-MM-DD" and "until YYYY-MM-DD"
+// parts. "no date" clears the due
+// date. The real API accepts far more.
+func parseDueString(s string, now time.Time) (*Due, error) {
+	text := strings.TrimSpace(s)
+	lower := strings.ToLower(text)
+
+	switch lower {
+	case "", "no date", "no due date":
+		return nil, nil
+	case "today":
+		return &Due{Date: now.Format("2006-01-02"), String: text, Lang: "en"}, nil
+	case "tomorrow":
+		return &Due{Date: now.AddDate(0, 0, 1).Format("2006-01-02"), String: text, Lang: "en"}, nil
+	}
+
+	if t, err := time.Parse("2006-01-02 15:04", text); err == nil {
+		return &Due{Date: t.Format("2006-01-02T15:04:05"), String: text, Lang: "en"}, nil
+	}
+	if t, err := time.Parse("2006-01-02", text); err == nil {
+		return &Due{Date: t.Format("2006-01-02"), String: text, Lang: "en"}, nil
+	}
+
+	if strings.HasPrefix(lower, "every") {
+		date := now.Format("2006-01-02")
+		if _, after, ok := strings.Cut(lower, " starting "); ok {
+			date = strings.Fields(after)[0]
+			if _, err := time.Parse("2006-01-02", date); err != nil {
+				return nil, fmt.Errorf("invalid start date in due string %q", s)
+			}
+		}
+		if _, after, ok := strings.Cut(lower, " at "); ok {
+			clock, err := time.Parse("15:04", strings.Fields(after)[0])
+			if err != nil {
+				return nil, fmt.Errorf("invalid time in due string %q", s)
+			}
+			date += clock.Format("T15:04:05")
+		}
+		return &Due{Date: date, String: text, Lang: "en", IsRecurring: true}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported due string %q", s)
+}
+
+var countPattern = regexp.MustCompile(`(?i)for \d+ times?`)
+
+// advance moves a recurring due date to its next occurrence. A rule
+// limited with "for N times" counts down in the due string, and one with
+// "until YYYY-MM-DD" stops after that day. It returns false when the rule
+// is not understood or has ended.
+func advance(due *Due) bool {
+	rule := strings.ToLower(due.String)
+	var until *time.Time
+	if before, after, ok := strings.Cut(rule, " until "); ok {
+		t, err := time.Parse("2006-01-02", strings.Fields(after)[0])
+		if err != nil {
+			return false
+		}
+		end := t.AddDate(0, 0, 1).Add(-time.Second)
+		rule, until = before, &end
+	}
+	var starting string
+	for _, sep := range []string{" starting ", " at "} {
+		if before, after, ok := strings.Cut(rule, sep); ok {
+			rule = before
+			if sep == " starting " {
+				starting, _, _ = strings.Cut(after, " ")
+			}
+		}
+	}
+	count := 0
+	if before, after, ok := strings.Cut(rule, " for "); ok {
+		if _, err := fmt.Sscanf(after, "%d time", &count); err != nil || count < 1 {
+			return false
+		}
+		rule = before
+	}
+
+	rec, err := protocol.ParseRecurrence(rule)
+	if err != nil {
+		return false
+	}
+	rec.Until = until
+	if start, err := time.Parse("2006-01-02", starting); err == nil {
+		// Monthly and yearly tasks stay on the starting date's day.
+		rec.ByMonthDay = start.Day()
+	}
+
+	layout := "2006-01-02"
+	if strings.Contains(due.Date, "T") {
+		layout = "2006-01-02T15:04:05"
+	}
+	from, err := time.Parse(layout, due.Date)
+	if err != nil {
+		return false
+	}
+	next, ok := rec.Next(from)
+	if !ok || count == 1 {
+		return false
+	}
+	due.Date = next.Format(layout)
+	if count > 1 {
+		left := fmt.Sprintf("for %d times", count-1)
+		if count == 2 {
+			left = "for 1 time"
+		}
+		due.String = countPattern.ReplaceAllString(due.String, left)
+	}
+	return true
+}
+
+func (s *Server) find(id string) *Task {
+	for _, t := range s.tasks {
+		if t.ID == id {
+			return t
+		}
+	}
+	return nil
+}
+
+func (s *Server) findProject(id string) *Project {
+	for _, p := range s.projects {
+		if p.ID == id {
+			return p
+		}
+	}
+	return nil
+}
+
+func (s *Server) findSection(id string) *Section {
+	for _, sec := range s.sections {
+		if sec.ID == id {
+			return sec
+		}
+	}
+	return nil
+}
+
+func (s *Server) registerLabels(names []string) {
+	for _, name := range names {
+		if !slices.ContainsFunc(s.labels, func(l *Label) bool { return l.Name == name }) {
+			s.labels = append(s.labels, &Label{ID: s.newID(), Name: name, Color: "charcoal", Order: len(s.labels) + 1})
+		}
+	}
+}
+
+func (s *Server) newID() string {
+	s.nextID++
+	return strconv.Itoa(s.nextID)
+}
+
+func (s *Server) now() time.Time {
+	if s.Now != nil {
+		return s.Now()
+	}
+	return time.Now()
+}
+
+func (s *Server) timestamp() string {
+	return s.now().UTC().Format(timestampLayout)
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
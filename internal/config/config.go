@@ -11,17 +11,23 @@ const (
 )
 
 type Config struct {
-	DataDir      string
-	DataFile     string
-	TodoistToken string
+	DataDir        string
+	DataFile       string
+	TodoistToken   string
+	TodoistProject string
+	TodoistSection string
+	AppleList      string
 }
 
 func Default() *Config {
 	home, _ := os.UserHomeDir()
 	return &Config{
-		DataDir:      filepath.Join(home, DefaultDataDir),
-		DataFile:     DefaultDataFile,
-		TodoistToken: os.Getenv("TODOIST_API_TOKEN"),
+		DataDir:        filepath.Join(home, DefaultDataDir),
+		DataFile:       DefaultDataFile,
+		TodoistToken:   os.Getenv("TODOIST_API_TOKEN"),
+		TodoistProject: os.Getenv("TODOIST_PROJECT"),
+		TodoistSection: os.Getenv("TODOIST_SECTION"),
+		AppleList:      os.Getenv("APPLE_REMINDERS_LIST"),
 	}
 }
 
@@ -0,0 +1,432 @@
+// Package dateparse parses natural-language due dates such as "tomorrow at
+// 14:30", "in 2 hours", "next friday" or "end of month".
+package dateparse
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata" // zone names work without system tzdata
+)
+
+// DefaultHour is the time of day used when an expression names a day but
+// no time.
+const DefaultHour = 9
+
+// Parser parses date expressions relative to a clock.
+type Parser struct {
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+
+	// Location is used for dates without an explicit zone.
+	// Defaults to the location of Now().
+	Location *time.Location
+
+	// DefaultHour is the hour used for dates without a time.
+	// Defaults to DefaultHour.
+	DefaultHour int
+}
+
+// Parse parses s using a Parser with default settings.
+func Parse(s string) (time.Time, error) {
+	return (&Parser{}).Parse(s)
+}
+
+func (p *Parser) now() time.Time {
+	now := time.Now()
+	if p.Now != nil {
+		now = p.Now()
+	}
+	if p.Location != nil {
+		now = now.In(p.Location)
+	}
+	return now
+}
+
+func (p *Parser) defaultHour() int {
+	if p.DefaultHour > 0 {
+		return p.DefaultHour
+	}
+	return DefaultHour
+}
+
+// clock is a time of day parsed from an expression.
+type clock struct {
+	hour, minute int
+}
+
+var (
+	relativeRe = regexp.MustCompile(`^in (an?|\d+) ?(minutes?|mins?|m|hours?|hrs?|h|days?|d|weeks?|w|months?|years?)$`)
+	clockRe    = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))? ?(am|pm)?$`)
+)
+
+// Parse parses a date expression. Supported forms include:
+//
+//	today, tonight, tomorrow, monday, next friday, next week, next month,
+//	end of week, end of month, in 2 hours, in 3 days, 3pm, 14:30,
+//	tomorrow at 14:30, friday 5pm, 2024-03-15, 2024-03-15 14:30,
+//	2024-03-15T14:30:00Z, 03/15/2024, Jan 2, March 15 2025
+//
+// A trailing zone ("UTC", "PST", "+02:00", "America/New_York") applies to
+// the whole expression. Dates without a year resolve to the next future
+// occurrence.
+func (p *Parser) Parse(s string) (time.Time, error) {
+	input := strings.ToLower(strings.Join(strings.Fields(s), " "))
+	if input == "" {
+		return time.Time{}, fmt.Errorf("empty date")
+	}
+
+	// Absolute timestamps carry their own zone.
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04Z07:00"} {
+		if t, err := time.Parse(layout, strings.ToUpper(input)); err == nil {
+			return t, nil
+		}
+	}
+
+	input, loc := p.splitZone(input)
+	now := p.now().In(loc)
+
+	// ISO datetimes without a zone are in the expression's location.
+	for _, layout := range []string{"2006-01-02T15:04:05", "2006-01-02T15:04"} {
+		if t, err := time.ParseInLocation(layout, strings.ToUpper(input), loc); err == nil {
+			return t, nil
+		}
+	}
+
+	if t, ok := p.parseRelative(input, now); ok {
+		return t, nil
+	}
+
+	datePart, clk, err := splitClock(input)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if datePart == "" {
+		if clk == nil {
+			return time.Time{}, fmt.Errorf("could not parse date: %s", s)
+		}
+		// A bare time means the next time the clock shows it.
+		t := at(now, *clk)
+		if !t.After(now) {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t, nil
+	}
+
+	day, ok := p.parseDay(datePart, now)
+	if !ok {
+		return time.Time{}, fmt.Errorf("could not parse date: %s", s)
+	}
+
+	if clk == nil {
+		switch datePart {
+		case "tonight":
+			clk = &clock{hour: 20}
+		case "end of day", "eod":
+			clk = &clock{hour: 17}
+		default:
+			clk = &clock{hour: p.defaultHour()}
+		}
+	}
+	return at(day, *clk), nil
+}
+
+// splitZone removes a trailing time zone from the expression.
+func (p *Parser) splitZone(input string) (string, *time.Location) {
+	loc := p.now().Location()
+
+	i := strings.LastIndex(input, " ")
+	if i < 0 {
+		return input, loc
+	}
+	rest, zone := input[:i], input[i+1:]
+
+	if z, ok := lookupZone(zone); ok {
+		return rest, z
+	}
+	return input, loc
+}
+
+var abbreviations = map[string]string{
+	"utc": "UTC", "gmt": "UTC", "z": "UTC",
+	"est": "America/New_York", "edt": "America/New_York",
+	"cst": "America/Chicago", "cdt": "America/Chicago",
+	"mst": "America/Denver", "mdt": "America/Denver",
+	"pst": "America/Los_Angeles", "pdt": "America/Los_Angeles",
+	"cet": "Europe/Paris", "cest": "Europe/Paris",
+	"bst": "Europe/London", "ist": "Asia/Kolkata", "jst": "Asia/Tokyo",
+}
+
+var offsetRe = regexp.MustCompile(`^(?:utc|gmt)?([+-])(\d{1,2})(?::?(\d{2}))?$`)
+
+func lookupZone(zone string) (*time.Location, bool) {
+	if name, ok := abbreviations[zone]; ok {
+		loc, err := time.LoadLocation(name)
+		return loc, err == nil
+	}
+
+	if m := offsetRe.FindStringSubmatch(zone); m != nil {
+		hours, _ := strconv.Atoi(m[2])
+		minutes, _ := strconv.Atoi(m[3])
+		offset := hours*3600 + minutes*60
+		if m[1] == "-" {
+			offset = -offset
+		}
+		return time.FixedZone(strings.ToUpper(zone), offset), true
+	}
+
+	// IANA names such as America/New_York; the input was lowercased.
+	if strings.Contains(zone, "/") {
+		parts := strings.Split(zone, "/")
+		for i, part := range parts {
+			words := strings.Split(part, "_")
+			for j, w := range words {
+				if w != "" {
+					words[j] = strings.ToUpper(w[:1]) + w[1:]
+				}
+			}
+			parts[i] = strings.Join(words, "_")
+		}
+		if loc, err := time.LoadLocation(strings.Join(parts, "/")); err == nil {
+			return loc, true
+		}
+	}
+	return nil, false
+}
+
+func (p *Parser) parseRelative(input string, now time.Time) (time.Time, bool) {
+	m := relativeRe.FindStringSubmatch(input)
+	if m == nil {
+		return time.Time{}, false
+	}
+
+	n := 1
+	if m[1] != "a" && m[1] != "an" {
+		n, _ = strconv.Atoi(m[1])
+	}
+
+	switch unit := strings.TrimSuffix(m[2], "s"); unit {
+	case "minute", "min", "m":
+		return now.Add(time.Duration(n) * time.Minute), true
+	case "hour", "hr", "h":
+		return now.Add(time.Duration(n) * time.Hour), true
+	case "day", "d":
+		return now.AddDate(0, 0, n), true
+	case "week", "w":
+		return now.AddDate(0, 0, 7*n), true
+	case "month":
+		return now.AddDate(0, n, 0), true
+	case "year":
+		return now.AddDate(n, 0, 0), true
+	}
+	return time.Time{}, false
+}
+
+// splitClock separates a trailing time of day ("at 3pm", "14:30") from the
+// date part of the expression.
+func splitClock(input string) (string, *clock, error) {
+	for word, c := range map[string]clock{"noon": {hour: 12}, "midnight": {}} {
+		if rest, ok := strings.CutSuffix(input, word); ok && (rest == "" || strings.HasSuffix(rest, " ")) {
+			return trimAt(rest), &c, nil
+		}
+	}
+
+	words := strings.Fields(input)
+	for take := min(2, len(words)); take >= 1; take-- {
+		candidate := strings.Join(words[len(words)-take:], " ")
+		m := clockRe.FindStringSubmatch(candidate)
+		if m == nil {
+			continue
+		}
+		// A bare number is a day of month ("jan 2"), not an hour.
+		if m[2] == "" && m[3] == "" {
+			continue
+		}
+
+		clk, err := toClock(m[1], m[2], m[3])
+		if err != nil {
+			return "", nil, err
+		}
+		return trimAt(strings.Join(words[:len(words)-take], " ")), clk, nil
+	}
+	return input, nil, nil
+}
+
+// trimAt removes the "at" joining a date to its time ("tomorrow at").
+func trimAt(s string) string {
+	s = strings.TrimSpace(s)
+	if s == "at" {
+		return ""
+	}
+	return strings.TrimSuffix(s, " at")
+}
+
+func toClock(hourStr, minuteStr, meridiem string) (*clock, error) {
+	hour, _ := strconv.Atoi(hourStr)
+	minute := 0
+	if minuteStr != "" {
+		minute, _ = strconv.Atoi(minuteStr)
+	}
+
+	switch meridiem {
+	case "am":
+		if hour < 1 || hour > 12 {
+			return nil, fmt.Errorf("invalid hour: %d%s", hour, meridiem)
+		}
+		if hour == 12 {
+			hour = 0
+		}
+	case "pm":
+		if hour < 1 || hour > 12 {
+			return nil, fmt.Errorf("invalid hour: %d%s", hour, meridiem)
+		}
+		if hour != 12 {
+			hour += 12
+		}
+	}
+
+	if hour > 23 || minute > 59 {
+		return nil, fmt.Errorf("invalid time: %s:%02d", hourStr, minute)
+	}
+	return &clock{hour: hour, minute: minute}, nil
+}
+
+// parseDay resolves the date part of an expression to a day (time of day
+// is ignored by the caller).
+func (p *Parser) parseDay(input string, now time.Time) (time.Time, bool) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	switch input {
+	case "today", "tonight":
+		return today, true
+	case "tomorrow", "tmr", "tmrw":
+		return today.AddDate(0, 0, 1), true
+	case "yesterday":
+		return today.AddDate(0, 0, -1), true
+	case "next week":
+		return nextWeekday(today, time.Monday), true
+	case "next month":
+		return time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, now.Location()), true
+	case "next year":
+		return time.Date(now.Year()+1, time.January, 1, 0, 0, 0, 0, now.Location()), true
+	case "end of day", "eod":
+		return today, true
+	case "end of week", "eow":
+		return nextOrSameWeekday(today, time.Friday), true
+	case "end of month", "eom":
+		return time.Date(now.Year(), now.Month()+1, 0, 0, 0, 0, 0, now.Location()), true
+	case "end of year", "eoy":
+		return time.Date(now.Year(), time.December, 31, 0, 0, 0, 0, now.Location()), true
+	}
+
+	if name, ok := strings.CutPrefix(input, "next "); ok {
+		if wd, ok := parseWeekday(name); ok {
+			// "next friday" is the friday of next week, never this week.
+			monday := nextWeekday(today, time.Monday)
+			return monday.AddDate(0, 0, (int(wd)+6)%7), true
+		}
+	}
+	if name, ok := strings.CutPrefix(input, "this "); ok {
+		if wd, ok := parseWeekday(name); ok {
+			return nextOrSameWeekday(today, wd), true
+		}
+	}
+	if wd, ok := parseWeekday(input); ok {
+		return nextWeekday(today, wd), true
+	}
+
+	return parseCalendarDate(input, today)
+}
+
+// Month names in layouts match case-insensitively.
+var fullDateLayouts = []string{
+	"2006-01-02",
+	"01/02/2006",
+	"1/2/2006",
+	"Jan 2 2006",
+	"Jan 2, 2006",
+	"January 2 2006",
+	"January 2, 2006",
+	"2 Jan 2006",
+	"2 January 2006",
+}
+
+var yearlessLayouts = []string{
+	"Jan 2",
+	"January 2",
+	"2 Jan",
+	"2 January",
+	"01/02",
+	"1/2",
+}
+
+func parseCalendarDate(input string, today time.Time) (time.Time, bool) {
+	loc := today.Location()
+	input = ordinalRe.ReplaceAllString(input, "$1")
+
+	for _, layout := range fullDateLayouts {
+		if t, err := time.ParseInLocation(layout, input, loc); err == nil {
+			return t, true
+		}
+	}
+
+	for _, layout := range yearlessLayouts {
+		t, err := time.ParseInLocation(layout, input, loc)
+		if err != nil {
+			continue
+		}
+		// Without a year, pick the next occurrence: "jan 2" in December
+		// means January of next year.
+		d := time.Date(today.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+		if d.Before(today) {
+			d = d.AddDate(1, 0, 0)
+		}
+		return d, true
+	}
+
+	return time.Time{}, false
+}
+
+var ordinalRe = regexp.MustCompile(`(\d+)(st|nd|rd|th)\b`)
+
+func parseWeekday(s string) (time.Weekday, bool) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		full := strings.ToLower(d.String())
+		if s == full || s == full[:3] {
+			return d, true
+		}
+	}
+	switch s {
+	case "tues":
+		return time.Tuesday, true
+	case "weds":
+		return time.Wednesday, true
+	case "thur", "thurs":
+		return time.Thursday, true
+	}
+	return 0, false
+}
+
+// nextWeekday returns the first weekday strictly after from.
+func nextWeekday(from time.Time, weekday time.Weekday) time.Time {
+	days := int(weekday - from.Weekday())
+	if days <= 0 {
+		days += 7
+	}
+	return from.AddDate(0, 0, days)
+}
+
+// nextOrSameWeekday returns from if it falls on weekday, else the next one.
+func nextOrSameWeekday(from time.Time, weekday time.Weekday) time.Time {
+	if from.Weekday() == weekday {
+		return from
+	}
+	return nextWeekday(from, weekday)
+}
+
+func at(day time.Time, c clock) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), c.hour, c.minute, 0, 0, day.Location())
+}
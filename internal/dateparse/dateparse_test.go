@@ -0,0 +1,150 @@
+package dateparse
+
+import (
+	"testing"
+	"time"
+)
+
+// Wednesday, Dec 17 2025 10:15 in New York.
+var (
+	newYork, _ = time.LoadLocation("America/New_York")
+	fixedNow   = time.Date(2025, 12, 17, 10, 15, 0, 0, newYork)
+)
+
+func newTestParser() *Parser {
+	return &Parser{Now: func() time.Time { return fixedNow }}
+}
+
+func date(year int, month time.Month, day, hour, minute int) time.Time {
+	return time.Date(year, month, day, hour, minute, 0, 0, newYork)
+}
+
+func TestParse(t *testing.T) {
+	utc := time.UTC
+	la, _ := time.LoadLocation("America/Los_Angeles")
+
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		// Named days
+		{"today", date(2025, 12, 17, 9, 0)},
+		{"Today", date(2025, 12, 17, 9, 0)},
+		{"tonight", date(2025, 12, 17, 20, 0)},
+		{"tomorrow", date(2025, 12, 18, 9, 0)},
+		{"eod", date(2025, 12, 17, 17, 0)},
+
+		// Weekdays
+		{"friday", date(2025, 12, 19, 9, 0)},
+		{"fri", date(2025, 12, 19, 9, 0)},
+		{"wednesday", date(2025, 12, 24, 9, 0)},
+		{"this friday", date(2025, 12, 19, 9, 0)},
+		{"next friday", date(2025, 12, 26, 9, 0)},
+		{"next monday", date(2025, 12, 22, 9, 0)},
+
+		// Periods
+		{"next week", date(2025, 12, 22, 9, 0)},
+		{"next month", date(2026, 1, 1, 9, 0)},
+		{"end of week", date(2025, 12, 19, 9, 0)},
+		{"end of month", date(2025, 12, 31, 9, 0)},
+		{"end of year", date(2025, 12, 31, 9, 0)},
+
+		// Times
+		{"3pm", date(2025, 12, 17, 15, 0)},
+		{"9am", date(2025, 12, 18, 9, 0)}, // already past today
+		{"14:30", date(2025, 12, 17, 14, 30)},
+		{"noon", date(2025, 12, 17, 12, 0)},
+		{"12am", date(2025, 12, 18, 0, 0)},
+		{"tomorrow at 14:30", date(2025, 12, 18, 14, 30)},
+		{"tomorrow 3pm", date(2025, 12, 18, 15, 0)},
+		{"friday at 5:15 pm", date(2025, 12, 19, 17, 15)},
+		{"tomorrow at noon", date(2025, 12, 18, 12, 0)},
+
+		// Relative offsets
+		{"in 2 hours", date(2025, 12, 17, 12, 15)},
+		{"in 30 minutes", date(2025, 12, 17, 10, 45)},
+		{"in an hour", date(2025, 12, 17, 11, 15)},
+		{"in 3 days", date(2025, 12, 20, 10, 15)},
+		{"in 2 weeks", date(2025, 12, 31, 10, 15)},
+		{"in 1 month", date(2026, 1, 17, 10, 15)},
+		{"in 90m", date(2025, 12, 17, 11, 45)},
+
+		// Calendar dates
+		{"2026-03-15", date(2026, 3, 15, 9, 0)},
+		{"2026-03-15 14:30", date(2026, 3, 15, 14, 30)},
+		{"03/15/2026", date(2026, 3, 15, 9, 0)},
+		{"March 15 2026", date(2026, 3, 15, 9, 0)},
+		{"march 15, 2026 at 4pm", date(2026, 3, 15, 16, 0)},
+		{"Dec 20", date(2025, 12, 20, 9, 0)},
+		{"Jan 2", date(2026, 1, 2, 9, 0)}, // next year, not last January
+		{"January 2nd", date(2026, 1, 2, 9, 0)},
+		{"2 jan", date(2026, 1, 2, 9, 0)},
+
+		// ISO datetimes
+		{"2026-03-15T14:30:00Z", time.Date(2026, 3, 15, 14, 30, 0, 0, utc)},
+		{"2026-03-15T14:30:00+02:00", time.Date(2026, 3, 15, 12, 30, 0, 0, utc)},
+		{"2026-03-15T14:30", date(2026, 3, 15, 14, 30)},
+
+		// Explicit zones
+		{"tomorrow 3pm UTC", time.Date(2025, 12, 18, 15, 0, 0, 0, utc)},
+		{"tomorrow 3pm PST", time.Date(2025, 12, 18, 15, 0, 0, 0, la)},
+		{"2026-03-15 09:00 America/Los_Angeles", time.Date(2026, 3, 15, 9, 0, 0, 0, la)},
+		{"friday 10am +05:30", time.Date(2025, 12, 19, 10, 0, 0, 0, time.FixedZone("", 5*3600+1800))},
+	}
+
+	p := newTestParser()
+	for _, tt := range tests {
+		got, err := p.Parse(tt.in)
+		if err != nil {
+			t.Errorf("Parse(%q) failed: %v", tt.in, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("Parse(%q) = %s, want %s", tt.in, got.Format(time.RFC3339), tt.want.Format(time.RFC3339))
+		}
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	p := newTestParser()
+	for _, in := range []string{
+		"",
+		"someday",
+		"next blursday",
+		"13pm",
+		"25:00",
+		"2026-13-45",
+		"in many hours",
+	} {
+		if got, err := p.Parse(in); err == nil {
+			t.Errorf("Parse(%q) = %s, expected error", in, got)
+		}
+	}
+}
+
+func TestParse_DefaultHour(t *testing.T) {
+	p := newTestParser()
+	p.DefaultHour = 8
+
+	got, err := p.Parse("tomorrow")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if want := date(2025, 12, 18, 8, 0); !got.Equal(want) {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}
+
+func TestParse_Location(t *testing.T) {
+	p := newTestParser()
+	p.Location = time.UTC
+
+	// 10:15 in New York is 15:15 UTC, so "today" is still Dec 17 in UTC.
+	got, err := p.Parse("today 18:00")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if want := time.Date(2025, 12, 17, 18, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}
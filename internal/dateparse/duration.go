@@ -0,0 +1,72 @@
+package dateparse
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var durationRe = regexp.MustCompile(`(\d+(?:\.\d+)?) ?(minutes?|mins?|m|hours?|hrs?|h|days?|d|weeks?|w)\b`)
+
+// ParseDuration parses a relative duration such as "2h", "30m", "1h30m",
+// "90 minutes", "3 days", "1 week" or "in 2 hours". Days and weeks are 24
+// and 168 hours.
+func ParseDuration(s string) (time.Duration, error) {
+	input := strings.TrimPrefix(strings.ToLower(strings.TrimSpace(s)), "in ")
+	if input == "" {
+		return 0, fmt.Errorf("empty duration")
+	}
+
+	if d, err := time.ParseDuration(input); err == nil {
+		if d <= 0 {
+			return 0, fmt.Errorf("duration must be positive: %s", s)
+		}
+		return d, nil
+	}
+
+	input = strings.NewReplacer(",", " ", " and ", " ").Replace(input)
+	if rest, ok := strings.CutPrefix(input, "an "); ok {
+		input = "1 " + rest
+	} else if rest, ok := strings.CutPrefix(input, "a "); ok {
+		input = "1 " + rest
+	}
+
+	matches := durationRe.FindAllStringSubmatchIndex(input, -1)
+	if matches == nil {
+		return 0, fmt.Errorf("could not parse duration: %s", s)
+	}
+
+	var total time.Duration
+	consumed := 0
+	for _, m := range matches {
+		// Only whitespace may separate the components.
+		if strings.TrimSpace(input[consumed:m[0]]) != "" {
+			return 0, fmt.Errorf("could not parse duration: %s", s)
+		}
+		consumed = m[1]
+
+		n, _ := strconv.ParseFloat(input[m[2]:m[3]], 64)
+		var unit time.Duration
+		switch strings.TrimSuffix(input[m[4]:m[5]], "s") {
+		case "minute", "min", "m":
+			unit = time.Minute
+		case "hour", "hr", "h":
+			unit = time.Hour
+		case "day", "d":
+			unit = 24 * time.Hour
+		case "week", "w":
+			unit = 7 * 24 * time.Hour
+		}
+		total += time.Duration(n * float64(unit))
+	}
+
+	if strings.TrimSpace(input[consumed:]) != "" {
+		return 0, fmt.Errorf("could not parse duration: %s", s)
+	}
+	if total <= 0 {
+		return 0, fmt.Errorf("duration must be positive: %s", s)
+	}
+	return total, nil
+}
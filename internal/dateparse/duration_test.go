@@ -0,0 +1,47 @@
+package dateparse
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"2h", 2 * time.Hour},
+		{"30m", 30 * time.Minute},
+		{"1h30m", 90 * time.Minute},
+		{"90 minutes", 90 * time.Minute},
+		{"1 hour", time.Hour},
+		{"an hour", time.Hour},
+		{"in 2 hours", 2 * time.Hour},
+		{"1d", 24 * time.Hour},
+		{"3 days", 72 * time.Hour},
+		{"1w", 7 * 24 * time.Hour},
+		{"2 weeks", 14 * 24 * time.Hour},
+		{"1 day 2 hours", 26 * time.Hour},
+		{"1 hour and 15 minutes", 75 * time.Minute},
+		{"1.5h", 90 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseDuration(tt.in)
+		if err != nil {
+			t.Errorf("ParseDuration(%q) failed: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseDuration(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseDuration_Invalid(t *testing.T) {
+	for _, in := range []string{"", "soon", "-2h", "0m", "2 fortnights", "2h later"} {
+		if got, err := ParseDuration(in); err == nil {
+			t.Errorf("ParseDuration(%q) = %s, expected error", in, got)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+// Package gitsync keeps the local reminders directory in a git repository,
+// so it can be shared between machines through any git remote.
+package gitsync
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DriverName is the name the reminders merge driver is registered under in
+// git config and .gitattributes.
+const DriverName = "recall"
+
+// Remote is the name of the remote Sync pulls from and pushes to.
+const Remote = "origin"
+
+// Branch is the branch Init creates, so clones on every machine agree.
+const Branch = "main"
+
+// ignored lists files in the data directory that must not be shared: lock
+// and temporary files, the Todoist token in .env, and sync state, whose
+// IDs are specific to one machine's backends.
+var ignored = []string{"*.lock", "*.tmp", ".env", "sync/"}
+
+var (
+	// ErrNotRepository is returned when the directory is not in a git
+	// repository.
+	ErrNotRepository = errors.New("not a git repository")
+
+	// ErrNoRemote is returned by Sync when there is no remote to sync
+	// with.
+	ErrNoRemote = errors.New("no git remote configured")
+)
+
+// Repo is a git repository holding the reminders file.
+type Repo struct {
+	// Dir is the directory git commands run in.
+	Dir string
+}
+
+// New returns the repository at dir.
+func New(dir string) *Repo {
+	return &Repo{Dir: dir}
+}
+
+// Exists reports whether Dir is the top of its own git repository. A data
+// directory inside some larger repository, such as a dotfiles checkout,
+// does not count, so mutations are never committed to it unasked.
+func (r *Repo) Exists() bool {
+	_, err := os.Stat(filepath.Join(r.Dir, ".git"))
+	return err == nil
+}
+
+// IsRepository reports whether Dir is inside a git work tree.
+func (r *Repo) IsRepository(ctx context.Context) bool {
+	out, err := r.git(ctx, "rev-parse", "--is-inside-work-tree")
+	return err == nil && out == "true"
+}
+
+// Setup registers the merge driver for file in .gitattributes and the
+// repository's git config. driver is the command git runs, such as
+// DriverCommand(exe). It is safe to run more than once.
+func (r *Repo) Setup(ctx context.Context, file, driver string) error {
+	if !r.IsRepository(ctx) {
+		return fmt.Errorf("%s: %w", r.Dir, ErrNotRepository)
+	}
+
+	if _, err := r.git(ctx, "config", "merge."+DriverName+".name", "Recall reminders merge"); err != nil {
+		return err
+	}
+	if _, err := r.git(ctx, "config", "merge."+DriverName+".driver", driver); err != nil {
+		return err
+	}
+
+	return addLine(filepath.Join(r.Dir, ".gitattributes"), file+" merge="+DriverName)
+}
+
+// Init makes Dir a git repository on Branch, if it is not one yet, with a
+// .gitignore for files that must not be shared and the merge driver set
+// up for file. When remote is set it is added as Remote. Any existing
+// files are committed. It is safe to run more than once.
+func (r *Repo) Init(ctx context.Context, file, driver, remote string) error {
+	if err := os.MkdirAll(r.Dir, 0755); err != nil {
+		return fmt.Errorf("creating directory: %w", err)
+	}
+	if !r.Exists() {
+		if _, err := r.git(ctx, "init", "-q"); err != nil {
+			return err
+		}
+		if _, err := r.git(ctx, "symbolic-ref", "HEAD", "refs/heads/"+Branch); err != nil {
+			return err
+		}
+	}
+
+	for _, pattern := range ignored {
+		if err := addLine(filepath.Join(r.Dir, ".gitignore"), pattern); err != nil {
+			return err
+		}
+	}
+	if err := r.Setup(ctx, file, driver); err != nil {
+		return err
+	}
+
+	if remote != "" {
+		if _, err := r.remoteURL(ctx); err == nil {
+			if _, err := r.git(ctx, "remote", "set-url", Remote, remote); err != nil {
+				return err
+			}
+		} else if _, err := r.git(ctx, "remote", "add", Remote, remote); err != nil {
+			return err
+		}
+	}
+
+	_, err := r.Commit(ctx, "init")
+	return err
+}
+
+// Commit commits paths with message, or every change in the work tree
+// when no paths are given. It reports false when there was nothing to
+// commit.
+func (r *Repo) Commit(ctx context.Context, message string, paths ...string) (bool, error) {
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+	if _, err := r.git(ctx, append([]string{"add", "-A", "--"}, paths...)...); err != nil {
+		return false, err
+	}
+
+	if _, err := r.git(ctx, append([]string{"diff", "--cached", "--quiet", "--"}, paths...)...); err == nil {
+		return false, nil
+	}
+	if _, err := r.git(ctx, append([]string{"commit", "-q", "-m", message, "--"}, paths...)...); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// DriverCommand returns the git merge driver command line that runs the
+// rc binary at exe.
+func DriverCommand(exe string) string {
+	return shellQuote(exe) + " merge-driver %O %A %B"
+}
+
+// addLine appends line to the file at path, such as .gitattributes,
+// unless it is already there.
+func addLine(path, line string) error {
+	name := filepath.Base(path)
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("reading %s: %w", name, err)
+	}
+
+	lines := strings.Split(string(data), "\n")
+	if slices.ContainsFunc(lines, func(l string) bool { return strings.TrimSpace(l) == line }) {
+		return nil
+	}
+
+	if len(data) > 0 && !bytes.HasSuffix(data, []byte("\n")) {
+		data = append(data, '\n')
+	}
+	data = append(data, line+"\n"...)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", name, err)
+	}
+	return nil
+}
+
+// indexLockRetries is how many times a git command is retried when
+// another git process, such as an editor's git integration, holds the
+// index lock. Each wait is indexLockWait longer than the last.
+const (
+	indexLockRetries = 5
+	indexLockWait    = 100 * time.Millisecond
+)
+
+// git runs a git command in the repository and returns its output without
+// the trailing newline.
+func (r *Repo) git(ctx context.Context, args ...string) (string, error) {
+	for attempt := 1; ; attempt++ {
+		cmd := exec.CommandContext(ctx, "git", append([]string{"-C", r.Dir}, args...)...)
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		err := cmd.Run()
+		if err == nil {
+			return strings.TrimRight(stdout.String(), "\n"), nil
+		}
+
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = strings.TrimSpace(stdout.String())
+		}
+		// git gives up before changing anything when the lock is taken,
+		// so the command can run again.
+		if attempt > indexLockRetries || !strings.Contains(msg, "index.lock") {
+			return "", fmt.Errorf("git %s: %w: %s", args[0], err, msg)
+		}
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(time.Duration(attempt) * indexLockWait):
+		}
+	}
+}
+
+// shellQuote quotes s for the POSIX shell git runs merge drivers with.
+func shellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("/._-+", r))
+	}) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
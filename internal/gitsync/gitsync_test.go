@@ -0,0 +1,357 @@
+package gitsync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shaneoxm/recall/internal/adapters/jsonl"
+	"github.com/shaneoxm/recall/internal/protocol"
+)
+
+// testRepo creates a git repository with an isolated configuration.
+func testRepo(t *testing.T) *Repo {
+	t.Helper()
+	isolateGit(t)
+
+	repo := New(t.TempDir())
+	run(t, repo, "init", "-q", "-b", Branch)
+	return repo
+}
+
+// isolateGit keeps tests from reading the user's git configuration.
+func isolateGit(t *testing.T) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+}
+
+func run(t *testing.T, repo *Repo, args ...string) string {
+	t.Helper()
+	out, err := repo.git(context.Background(), args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func TestSetup(t *testing.T) {
+	repo := testRepo(t)
+	ctx := context.Background()
+
+	for range 2 {
+		if err := repo.Setup(ctx, "reminders.jsonl", "rc merge-driver %O %A %B"); err != nil {
+			t.Fatalf("Setup failed: %v", err)
+		}
+	}
+
+	data, _ := os.ReadFile(filepath.Join(repo.Dir, ".gitattributes"))
+	if string(data) != "reminders.jsonl merge=recall\n" {
+		t.Errorf("unexpected .gitattributes: %q", data)
+	}
+	if got := run(t, repo, "config", "merge.recall.driver"); got != "rc merge-driver %O %A %B" {
+		t.Errorf("unexpected driver: %q", got)
+	}
+}
+
+func TestSetup_NotRepository(t *testing.T) {
+	isolateGit(t)
+	t.Setenv("GIT_CEILING_DIRECTORIES", os.TempDir())
+
+	err := New(t.TempDir()).Setup(context.Background(), "reminders.jsonl", "rc merge-driver %O %A %B")
+	if err == nil || !strings.Contains(err.Error(), ErrNotRepository.Error()) {
+		t.Errorf("expected ErrNotRepository, got %v", err)
+	}
+}
+
+func TestDriverCommand(t *testing.T) {
+	tests := map[string]string{
+		"/usr/local/bin/rc":   "/usr/local/bin/rc merge-driver %O %A %B",
+		"/Users/me/my bin/rc": "'/Users/me/my bin/rc' merge-driver %O %A %B",
+		"/tmp/it's/rc":        `'/tmp/it'\''s/rc' merge-driver %O %A %B`,
+	}
+	for exe, want := range tests {
+		if got := DriverCommand(exe); got != want {
+			t.Errorf("DriverCommand(%q) = %q, want %q", exe, got, want)
+		}
+	}
+}
+
+// TestMergeDriver merges two branches that edited the same reminder.
+func TestMergeDriver(t *testing.T) {
+	repo := testRepo(t)
+	ctx := context.Background()
+	if err := repo.Setup(ctx, "reminders.jsonl", testDriver(t)); err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+
+	path := filepath.Join(repo.Dir, "reminders.jsonl")
+	store, _ := jsonl.New(path)
+	r := protocol.NewReminder("Call")
+	store.Add(ctx, r)
+	run(t, repo, "add", "-A")
+	run(t, repo, "commit", "-q", "-m", "add: Call")
+
+	run(t, repo, "checkout", "-q", "-b", "phone")
+	r.Title = "Call mom"
+	store.Update(ctx, r)
+	run(t, repo, "commit", "-q", "-am", "edit: Call mom")
+
+	run(t, repo, "checkout", "-q", "main")
+	store.Complete(ctx, r.ID)
+	run(t, repo, "commit", "-q", "-am", "complete: Call")
+
+	if _, err := repo.git(ctx, "merge", "-q", "--no-edit", "phone"); err != nil {
+		t.Fatalf("merge failed: %v", err)
+	}
+
+	got, err := store.Get(ctx, r.ID)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got.Title != "Call mom" || !got.Completed {
+		t.Errorf("expected completed \"Call mom\", got %q completed=%t", got.Title, got.Completed)
+	}
+}
+
+// testDriver returns a merge driver command that runs this test binary as
+// rc merge-driver.
+func testDriver(t *testing.T) string {
+	t.Setenv("RECALL_GITSYNC_HELPER", "1")
+	return shellQuote(os.Args[0]) + " -test.run=^TestHelperDriver$ -- %O %A %B"
+}
+
+// TestHelperDriver is run by git as the merge driver in TestMergeDriver.
+func TestHelperDriver(t *testing.T) {
+	if os.Getenv("RECALL_GITSYNC_HELPER") != "1" {
+		t.Skip("helper process")
+	}
+	args := os.Args
+	for i, arg := range args {
+		if arg == "--" {
+			args = args[i+1:]
+			break
+		}
+	}
+	if _, err := jsonl.Merge(args[0], args[1], args[2]); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// machine is one clone of the reminders repository.
+type machine struct {
+	repo  *Repo
+	store *Store
+}
+
+// newMachine initializes a data directory that syncs with remote.
+func newMachine(t *testing.T, remote string) *machine {
+	t.Helper()
+	repo := New(filepath.Join(t.TempDir(), ".recall"))
+	if err := repo.Init(context.Background(), "reminders.jsonl", testDriver(t), remote); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	local, err := jsonl.New(filepath.Join(repo.Dir, "reminders.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &machine{repo: repo, store: NewStore(local, repo, "reminders.jsonl")}
+}
+
+func (m *machine) sync(t *testing.T) *SyncResult {
+	t.Helper()
+	result, err := m.store.Sync(context.Background())
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	return result
+}
+
+// bareRemote creates an empty bare repository to sync through.
+func bareRemote(t *testing.T) string {
+	t.Helper()
+	isolateGit(t)
+	dir := filepath.Join(t.TempDir(), "remote.git")
+	if out, err := exec.Command("git", "init", "-q", "--bare", dir).CombinedOutput(); err != nil {
+		t.Fatalf("git init --bare: %v: %s", err, out)
+	}
+	return dir
+}
+
+func TestInit(t *testing.T) {
+	isolateGit(t)
+	m := newMachine(t, "")
+	if err := m.repo.Init(context.Background(), "reminders.jsonl", "rc merge-driver %O %A %B", ""); err != nil {
+		t.Fatalf("second Init failed: %v", err)
+	}
+
+	data, _ := os.ReadFile(filepath.Join(m.repo.Dir, ".gitignore"))
+	if string(data) != "*.lock\n*.tmp\n.env\nsync/\n" {
+		t.Errorf("unexpected .gitignore: %q", data)
+	}
+	if got := run(t, m.repo, "log", "--format=%s"); got != "init" {
+		t.Errorf("expected a single init commit, got %q", got)
+	}
+}
+
+func TestStore_Commits(t *testing.T) {
+	isolateGit(t)
+	m := newMachine(t, "")
+	ctx := context.Background()
+
+	r := protocol.NewReminder("Call mom")
+	m.store.Add(ctx, r)
+	r.SetPriority(3)
+	m.store.Update(ctx, r)
+	r.Snooze(r.CreatedAt.Add(time.Hour))
+	m.store.Update(ctx, r)
+	m.store.Complete(ctx, r.ID)
+	m.store.Delete(ctx, r.ID)
+
+	want := "delete: Call mom\ncomplete: Call mom\nsnooze: Call mom\nedit: Call mom\nadd: Call mom\ninit"
+	if got := run(t, m.repo, "log", "--format=%s"); got != want {
+		t.Errorf("expected commits\n%s\ngot\n%s", want, got)
+	}
+
+	// The lock file is ignored, so nothing is left uncommitted.
+	if got := run(t, m.repo, "status", "--porcelain"); got != "" {
+		t.Errorf("expected a clean work tree, got %q", got)
+	}
+}
+
+func TestStore_Concurrent(t *testing.T) {
+	m := newMachine(t, bareRemote(t))
+	ctx := context.Background()
+
+	// Separate stores on one file stand in for rc processes running at
+	// once, some of them syncing.
+	const writers, adds = 4, 5
+	errs := make(chan error, writers*adds+writers)
+	var wg sync.WaitGroup
+	for w := range writers {
+		local, err := jsonl.New(filepath.Join(m.repo.Dir, "reminders.jsonl"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		store := NewStore(local, m.repo, "reminders.jsonl")
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range adds {
+				errs <- store.Add(ctx, protocol.NewReminder(fmt.Sprintf("Reminder %d.%d", w, i)))
+			}
+			_, err := store.Sync(ctx)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("concurrent change failed: %v", err)
+		}
+	}
+
+	if got := run(t, m.repo, "rev-list", "--count", "HEAD"); got != strconv.Itoa(writers*adds+1) {
+		t.Errorf("expected %d commits, got %s", writers*adds+1, got)
+	}
+	if got := run(t, m.repo, "status", "--porcelain"); got != "" {
+		t.Errorf("expected a clean work tree, got %q", got)
+	}
+	if reminders, _ := m.store.List(ctx, nil); len(reminders) != writers*adds {
+		t.Errorf("expected %d reminders, got %d", writers*adds, len(reminders))
+	}
+}
+
+func TestSync(t *testing.T) {
+	remote := bareRemote(t)
+	laptop := newMachine(t, remote)
+	desktop := newMachine(t, remote)
+	ctx := context.Background()
+
+	r := protocol.NewReminder("Call")
+	laptop.store.Add(ctx, r)
+	if result := laptop.sync(t); result.Pushed != 2 {
+		t.Errorf("expected to push 2 commits, got %d", result.Pushed)
+	}
+	desktop.sync(t)
+
+	// Both machines change the same reminder, and each adds one.
+	r.Title = "Call mom"
+	laptop.store.Update(ctx, r)
+	laptop.store.Add(ctx, protocol.NewReminder("Pay rent"))
+	desktop.store.Complete(ctx, r.ID)
+	desktop.store.Add(ctx, protocol.NewReminder("Buy milk"))
+
+	laptop.sync(t)
+	if result := desktop.sync(t); result.Pulled != 2 || result.Pushed != 2 {
+		t.Errorf("expected to pull and push 2 commits, got %+v", result)
+	}
+	laptop.sync(t)
+
+	for name, m := range map[string]*machine{"laptop": laptop, "desktop": desktop} {
+		reminders, err := m.store.List(ctx, &protocol.ListFilter{IncludeCompleted: true})
+		if err != nil {
+			t.Fatalf("%s: List failed: %v", name, err)
+		}
+		var got []string
+		for _, r := range reminders {
+			got = append(got, fmt.Sprintf("%s:%t", r.Title, r.Completed))
+		}
+		want := []string{"Call mom:true", "Pay rent:false", "Buy milk:false"}
+		if !slices.Equal(got, want) {
+			t.Errorf("%s: expected %v, got %v", name, want, got)
+		}
+	}
+
+	status, err := laptop.repo.Status(ctx, true)
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if status.Ahead != 0 || status.Behind != 0 || len(status.Changed) != 0 {
+		t.Errorf("expected laptop to be in sync, got %+v", status)
+	}
+}
+
+func TestSync_NoRemote(t *testing.T) {
+	isolateGit(t)
+	m := newMachine(t, "")
+	if _, err := m.repo.Sync(context.Background()); !errors.Is(err, ErrNoRemote) {
+		t.Errorf("expected ErrNoRemote, got %v", err)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	remote := bareRemote(t)
+	m := newMachine(t, remote)
+	ctx := context.Background()
+	m.sync(t)
+
+	m.store.Add(ctx, protocol.NewReminder("Call"))
+	os.WriteFile(filepath.Join(m.repo.Dir, "notes.txt"), []byte("x"), 0644)
+
+	status, err := m.repo.Status(ctx, false)
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	want := &Status{Branch: Branch, Remote: remote, Ahead: 1, Changed: []string{"notes.txt"}, LastCommit: "add: Call"}
+	if fmt.Sprint(status) != fmt.Sprint(want) {
+		t.Errorf("expected %+v, got %+v", want, status)
+	}
+}
@@ -0,0 +1,123 @@
+package gitsync
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shaneoxm/recall/internal/protocol"
+)
+
+// Store wraps the local store and commits the reminders file after every
+// change, with a message such as "complete: Call mom". When the wrapped
+// store is a Locker, each change and its commit are made under its lock,
+// so rc processes running at once do not race for git's index.
+type Store struct {
+	protocol.Store
+	repo *Repo
+	file string
+}
+
+// Locker is implemented by stores that can keep their file locked across
+// several steps, like *jsonl.Store. Store operations given the context
+// passed to fn run under the lock.
+type Locker interface {
+	Locked(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// NewStore returns inner with changes to file, relative to the repository,
+// committed to repo.
+func NewStore(inner protocol.Store, repo *Repo, file string) *Store {
+	return &Store{Store: inner, repo: repo, file: file}
+}
+
+// Unwrap returns the wrapped store.
+func (s *Store) Unwrap() protocol.Store {
+	return s.Store
+}
+
+// Repo returns the repository changes are committed to.
+func (s *Store) Repo() *Repo {
+	return s.repo
+}
+
+// Locked runs fn under the wrapped store's lock, or directly when it has
+// none.
+func (s *Store) Locked(ctx context.Context, fn func(ctx context.Context) error) error {
+	if l, ok := s.Store.(Locker); ok {
+		return l.Locked(ctx, fn)
+	}
+	return fn(ctx)
+}
+
+// Sync runs Repo.Sync under the lock, so no other rc process changes the
+// file while it is committed, rebased and pushed.
+func (s *Store) Sync(ctx context.Context) (*SyncResult, error) {
+	var result *SyncResult
+	err := s.Locked(ctx, func(ctx context.Context) error {
+		var err error
+		result, err = s.repo.Sync(ctx)
+		return err
+	})
+	return result, err
+}
+
+// Add implements protocol.Store.
+func (s *Store) Add(ctx context.Context, reminder *protocol.Reminder) error {
+	return s.Locked(ctx, func(ctx context.Context) error {
+		if err := s.Store.Add(ctx, reminder); err != nil {
+			return err
+		}
+		return s.commit(ctx, "add", reminder.Title)
+	})
+}
+
+// Update implements protocol.Store. Snoozes are committed as such.
+func (s *Store) Update(ctx context.Context, reminder *protocol.Reminder) error {
+	return s.Locked(ctx, func(ctx context.Context) error {
+		verb := "edit"
+		if old, err := s.Store.Get(ctx, reminder.ID); err == nil && reminder.SnoozeCount > old.SnoozeCount {
+			verb = "snooze"
+		}
+		if err := s.Store.Update(ctx, reminder); err != nil {
+			return err
+		}
+		return s.commit(ctx, verb, reminder.Title)
+	})
+}
+
+// Delete implements protocol.Store.
+func (s *Store) Delete(ctx context.Context, id string) error {
+	return s.Locked(ctx, func(ctx context.Context) error {
+		r, err := s.Store.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.Store.Delete(ctx, id); err != nil {
+			return err
+		}
+		return s.commit(ctx, "delete", r.Title)
+	})
+}
+
+// Complete implements protocol.Store.
+func (s *Store) Complete(ctx context.Context, id string) error {
+	return s.Locked(ctx, func(ctx context.Context) error {
+		r, err := s.Store.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.Store.Complete(ctx, id); err != nil {
+			return err
+		}
+		return s.commit(ctx, "complete", r.Title)
+	})
+}
+
+// commit records a change. The change itself is already saved, so the
+// error says so.
+func (s *Store) commit(ctx context.Context, verb, title string) error {
+	if _, err := s.repo.Commit(ctx, verb+": "+title, s.file); err != nil {
+		return fmt.Errorf("reminder saved but not committed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package gitsync
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SyncResult describes what Sync did.
+type SyncResult struct {
+	// Committed is set when uncommitted changes were committed first.
+	Committed bool `json:"committed" yaml:"committed"`
+
+	// Pulled is the number of commits taken from the remote.
+	Pulled int `json:"pulled" yaml:"pulled"`
+
+	// Pushed is the number of commits sent to the remote.
+	Pushed int `json:"pushed" yaml:"pushed"`
+}
+
+// Sync commits any outstanding changes, rebases them onto the remote
+// branch, which runs the merge driver for reminders edited on both sides,
+// and pushes the result. A rebase that still conflicts is aborted, leaving
+// the repository as it was.
+func (r *Repo) Sync(ctx context.Context) (*SyncResult, error) {
+	if !r.Exists() {
+		return nil, fmt.Errorf("%s: %w", r.Dir, ErrNotRepository)
+	}
+	if _, err := r.remoteURL(ctx); err != nil {
+		return nil, err
+	}
+	branch, err := r.branch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SyncResult{}
+	if result.Committed, err = r.Commit(ctx, "sync: local changes"); err != nil {
+		return nil, err
+	}
+
+	if _, err := r.git(ctx, "fetch", "-q", Remote); err != nil {
+		return nil, err
+	}
+	upstream := Remote + "/" + branch
+	hasUpstream := r.hasRef(ctx, "refs/remotes/"+upstream)
+
+	if hasUpstream {
+		if result.Pulled, err = r.count(ctx, "HEAD.."+upstream); err != nil {
+			return nil, err
+		}
+		if result.Pulled > 0 {
+			if _, err := r.git(ctx, "rebase", "-q", upstream); err != nil {
+				r.git(ctx, "rebase", "--abort")
+				return nil, fmt.Errorf("rebasing onto %s: %w", upstream, err)
+			}
+		}
+	}
+
+	ahead := "HEAD"
+	if hasUpstream {
+		ahead = upstream + "..HEAD"
+	}
+	if r.hasRef(ctx, "HEAD") {
+		if result.Pushed, err = r.count(ctx, ahead); err != nil {
+			return nil, err
+		}
+	}
+	if result.Pushed > 0 {
+		if _, err := r.git(ctx, "push", "-q", "-u", Remote, branch); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// Status is the state of the repository.
+type Status struct {
+	Branch string `json:"branch" yaml:"branch"`
+
+	// Remote is the URL of Remote, or empty when there is none.
+	Remote string `json:"remote,omitempty" yaml:"remote,omitempty"`
+
+	// Ahead and Behind count the commits not yet pushed and not yet
+	// pulled, as of the last fetch.
+	Ahead  int `json:"ahead" yaml:"ahead"`
+	Behind int `json:"behind" yaml:"behind"`
+
+	// Changed lists files with uncommitted changes.
+	Changed []string `json:"changed,omitempty" yaml:"changed,omitempty"`
+
+	// LastCommit is the subject of the latest commit.
+	LastCommit string `json:"last_commit,omitempty" yaml:"last_commit,omitempty"`
+}
+
+// Status reports the state of the repository. With fetch, the remote is
+// fetched first so Behind is current.
+func (r *Repo) Status(ctx context.Context, fetch bool) (*Status, error) {
+	if !r.Exists() {
+		return nil, fmt.Errorf("%s: %w", r.Dir, ErrNotRepository)
+	}
+	branch, err := r.branch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	status := &Status{Branch: branch}
+
+	if url, err := r.remoteURL(ctx); err == nil {
+		status.Remote = url
+		if fetch {
+			if _, err := r.git(ctx, "fetch", "-q", Remote); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	hasHead := r.hasRef(ctx, "HEAD")
+	upstream := "refs/remotes/" + Remote + "/" + branch
+	switch {
+	case hasHead && r.hasRef(ctx, upstream):
+		if status.Ahead, err = r.count(ctx, upstream+"..HEAD"); err != nil {
+			return nil, err
+		}
+		if status.Behind, err = r.count(ctx, "HEAD.."+upstream); err != nil {
+			return nil, err
+		}
+	case hasHead:
+		if status.Ahead, err = r.count(ctx, "HEAD"); err != nil {
+			return nil, err
+		}
+	}
+
+	out, err := r.git(ctx, "status", "--porcelain")
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if len(line) > 3 {
+			status.Changed = append(status.Changed, line[3:])
+		}
+	}
+
+	if hasHead {
+		if status.LastCommit, err = r.git(ctx, "log", "-1", "--format=%s"); err != nil {
+			return nil, err
+		}
+	}
+	return status, nil
+}
+
+// remoteURL returns the URL of Remote, or ErrNoRemote.
+func (r *Repo) remoteURL(ctx context.Context) (string, error) {
+	url, err := r.git(ctx, "remote", "get-url", Remote)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", r.Dir, ErrNoRemote)
+	}
+	return url, nil
+}
+
+// branch returns the checked out branch.
+func (r *Repo) branch(ctx context.Context) (string, error) {
+	return r.git(ctx, "symbolic-ref", "--short", "HEAD")
+}
+
+func (r *Repo) hasRef(ctx context.Context, ref string) bool {
+	_, err := r.git(ctx, "rev-parse", "--verify", "-q", ref)
+	return err == nil
+}
+
+// count returns the number of commits in a revision range.
+func (r *Repo) count(ctx context.Context, revs string) (int, error) {
+	out, err := r.git(ctx, "rev-list", "--count", revs)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(out)
+}
@@ -0,0 +1,335 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shaneoxm/recall/internal/adapters/jsonl"
+	"github.com/shaneoxm/recall/internal/protocol"
+)
+
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+
+	store, err := jsonl.New(filepath.Join(t.TempDir(), "reminders.jsonl"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	return New(store, "test")
+}
+
+// roundTrip sends each message to the server and returns the decoded responses.
+func roundTrip(t *testing.T, s *Server, messages ...string) []map[string]any {
+	t.Helper()
+
+	var out bytes.Buffer
+	in := strings.NewReader(strings.Join(messages, "\n") + "\n")
+	if err := s.Serve(context.Background(), in, &out); err != nil {
+		t.Fatalf("serve failed: %v", err)
+	}
+
+	var responses []map[string]any
+	scanner := bufio.NewScanner(&out)
+	for scanner.Scan() {
+		var resp map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &resp); err != nil {
+			t.Fatalf("invalid response %q: %v", scanner.Text(), err)
+		}
+		responses = append(responses, resp)
+	}
+	return responses
+}
+
+func callTool(t *testing.T, s *Server, name string, args any) map[string]any {
+	t.Helper()
+
+	data, _ := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "tools/call",
+		"params":  map[string]any{"name": name, "arguments": args},
+	})
+	responses := roundTrip(t, s, string(data))
+	if len(responses) != 1 {
+		t.Fatalf("expected 1 response, got %d", len(responses))
+	}
+	result, ok := responses[0]["result"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected result, got %v", responses[0])
+	}
+	return result
+}
+
+// callToolError calls a tool that is expected to fail with a JSON-RPC
+// error and returns its code.
+func callToolError(t *testing.T, s *Server, name string, args any) float64 {
+	t.Helper()
+
+	data, _ := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "tools/call",
+		"params":  map[string]any{"name": name, "arguments": args},
+	})
+	responses := roundTrip(t, s, string(data))
+	rpcErr, ok := responses[0]["error"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected error, got %v", responses[0])
+	}
+	return rpcErr["code"].(float64)
+}
+
+func TestServer_Initialize(t *testing.T) {
+	s := newTestServer(t)
+
+	responses := roundTrip(t, s,
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26"}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"ping"}`,
+	)
+
+	if len(responses) != 2 {
+		t.Fatalf("expected 2 responses (notification gets none), got %d", len(responses))
+	}
+
+	result := responses[0]["result"].(map[string]any)
+	if result["protocolVersion"] != "2025-03-26" {
+		t.Errorf("expected negotiated version 2025-03-26, got %v", result["protocolVersion"])
+	}
+	if _, ok := result["capabilities"].(map[string]any)["tools"]; !ok {
+		t.Error("expected tools capability")
+	}
+}
+
+func TestServer_ToolsList(t *testing.T) {
+	s := newTestServer(t)
+
+	responses := roundTrip(t, s, `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`)
+	tools := responses[0]["result"].(map[string]any)["tools"].([]any)
+
+	names := make(map[string]map[string]any)
+	for _, tl := range tools {
+		m := tl.(map[string]any)
+		names[m["name"].(string)] = m
+	}
+
+	for _, want := range []string{"add_reminder", "list_reminders", "get_reminder", "update_reminder", "complete_reminder", "snooze_reminder", "delete_reminder"} {
+		if _, ok := names[want]; !ok {
+			t.Errorf("missing tool %q", want)
+		}
+	}
+
+	add := names["add_reminder"]["inputSchema"].(map[string]any)
+	required := add["required"].([]any)
+	if len(required) != 1 || required[0] != "title" {
+		t.Errorf("expected only title to be required, got %v", required)
+	}
+
+	list := names["list_reminders"]["inputSchema"].(map[string]any)
+	props := list["properties"].(map[string]any)
+	due := props["due_before"].(map[string]any)
+	if due["format"] != "date-time" {
+		t.Errorf("expected due_before to be a date-time, got %v", due)
+	}
+}
+
+// checkSchema reports where v, decoded JSON, does not match schema. It
+// covers the keywords schemaFor emits.
+func checkSchema(t *testing.T, path string, schema map[string]any, v any) {
+	t.Helper()
+
+	ok := true
+	switch schema["type"] {
+	case "object":
+		obj, isObj := v.(map[string]any)
+		if ok = isObj; !ok {
+			break
+		}
+		for _, name := range asSlice(schema["required"]) {
+			if _, present := obj[name.(string)]; !present {
+				t.Errorf("%s: missing required %q", path, name)
+			}
+		}
+		props, _ := schema["properties"].(map[string]any)
+		extra, _ := schema["additionalProperties"].(map[string]any)
+		for name, value := range obj {
+			if prop, found := props[name].(map[string]any); found {
+				checkSchema(t, path+"."+name, prop, value)
+			} else if extra != nil {
+				checkSchema(t, path+"."+name, extra, value)
+			}
+		}
+	case "array":
+		items, isArr := v.([]any)
+		if ok = isArr; !ok {
+			break
+		}
+		for i, item := range items {
+			checkSchema(t, fmt.Sprintf("%s[%d]", path, i), schema["items"].(map[string]any), item)
+		}
+	case "string":
+		_, ok = v.(string)
+	case "integer", "number":
+		_, ok = v.(float64)
+	case "boolean":
+		_, ok = v.(bool)
+	}
+	if !ok {
+		t.Errorf("%s: %v (%T) is not of type %v", path, v, v, schema["type"])
+	}
+}
+
+func asSlice(v any) []any {
+	s, _ := v.([]any)
+	return s
+}
+
+func TestServer_OutputSchema(t *testing.T) {
+	s := newTestServer(t)
+
+	responses := roundTrip(t, s, `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`)
+	schemas := map[string]map[string]any{}
+	for _, tl := range responses[0]["result"].(map[string]any)["tools"].([]any) {
+		m := tl.(map[string]any)
+		schemas[m["name"].(string)], _ = m["outputSchema"].(map[string]any)
+	}
+
+	added := callTool(t, s, "add_reminder", map[string]any{
+		"title":  "Water plants",
+		"due":    "2030-01-15T09:00:00Z",
+		"repeat": "every 2 weeks",
+		"tags":   []string{"home"},
+	})
+	reminder := added["structuredContent"].(map[string]any)
+	if reminder["recurrence"] != "FREQ=WEEKLY;INTERVAL=2" {
+		t.Fatalf("expected the recurrence as RRULE text, got %v", reminder["recurrence"])
+	}
+	checkSchema(t, "add_reminder", schemas["add_reminder"], reminder)
+
+	id := reminder["id"]
+	for name, args := range map[string]map[string]any{
+		"get_reminder":      {"id": id},
+		"update_reminder":   {"id": id, "notes": "Use the blue can"},
+		"list_reminders":    {},
+		"complete_reminder": {"id": id},
+	} {
+		result := callTool(t, s, name, args)
+		checkSchema(t, name, schemas[name], result["structuredContent"])
+	}
+}
+
+func TestServer_ReminderLifecycle(t *testing.T) {
+	s := newTestServer(t)
+
+	added := callTool(t, s, "add_reminder", map[string]any{
+		"title": "Call mom",
+		"due":   "2030-01-15T09:00:00Z",
+		"tags":  []string{"family"},
+	})
+	reminder := added["structuredContent"].(map[string]any)
+	id := reminder["id"].(string)
+	if id == "" {
+		t.Fatal("expected an ID")
+	}
+
+	listed := callTool(t, s, "list_reminders", map[string]any{"tags": []string{"family"}})
+	reminders := listed["structuredContent"].(map[string]any)["reminders"].([]any)
+	if len(reminders) != 1 {
+		t.Fatalf("expected 1 reminder, got %d", len(reminders))
+	}
+
+	updated := callTool(t, s, "update_reminder", map[string]any{"id": id, "notes": "Birthday next week"})
+	if updated["structuredContent"].(map[string]any)["notes"] != "Birthday next week" {
+		t.Errorf("expected notes to be updated, got %v", updated["structuredContent"])
+	}
+
+	completed := callTool(t, s, "complete_reminder", map[string]any{"id": id})
+	if completed["structuredContent"].(map[string]any)["completed"] != true {
+		t.Error("expected reminder to be completed")
+	}
+
+	callTool(t, s, "delete_reminder", map[string]any{"id": id})
+
+	if code := callToolError(t, s, "get_reminder", map[string]any{"id": id}); code != codeInvalidParams {
+		t.Errorf("expected invalid params for deleted reminder, got %v", code)
+	}
+}
+
+func TestServer_Snooze(t *testing.T) {
+	s := newTestServer(t)
+
+	added := callTool(t, s, "add_reminder", map[string]any{"title": "Stretch"})
+	id := added["structuredContent"].(map[string]any)["id"].(string)
+
+	result := callTool(t, s, "snooze_reminder", map[string]any{"id": id, "duration": "2h"})
+	snoozed := result["structuredContent"].(map[string]any)
+	if snoozed["snooze_count"] != float64(1) {
+		t.Errorf("expected snooze_count 1, got %v", snoozed["snooze_count"])
+	}
+	if snoozed["due"] == nil || snoozed["last_snoozed_at"] == nil {
+		t.Errorf("expected due and last_snoozed_at to be set, got %v", snoozed)
+	}
+
+	if code := callToolError(t, s, "snooze_reminder", map[string]any{"id": id}); code != codeInvalidParams {
+		t.Errorf("expected invalid params without duration or until, got %v", code)
+	}
+
+	past := time.Now().Add(-time.Hour).Format("2006-01-02 15:04")
+	if code := callToolError(t, s, "snooze_reminder", map[string]any{"id": id, "until": past}); code != codeInvalidParams {
+		t.Errorf("expected invalid params for an until in the past, got %v", code)
+	}
+}
+
+func TestServer_InvalidDue(t *testing.T) {
+	s := newTestServer(t)
+
+	if code := callToolError(t, s, "add_reminder", map[string]any{"title": "Test", "due": "someday"}); code != codeInvalidParams {
+		t.Errorf("expected invalid params for unparseable due date, got %v", code)
+	}
+}
+
+// brokenStore fails every operation, as an unreachable backend would.
+type brokenStore struct {
+	protocol.Store
+}
+
+func (brokenStore) List(ctx context.Context, filter *protocol.ListFilter) ([]*protocol.Reminder, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestServer_BackendFailure(t *testing.T) {
+	s := New(brokenStore{}, "test")
+
+	result := callTool(t, s, "list_reminders", map[string]any{})
+	if result["isError"] != true {
+		t.Errorf("expected isError for a backend failure, got %v", result)
+	}
+	text := result["content"].([]any)[0].(map[string]any)["text"]
+	if text != "listing reminders: connection refused" {
+		t.Errorf("unexpected error text: %v", text)
+	}
+}
+
+func TestServer_UnknownMethod(t *testing.T) {
+	s := newTestServer(t)
+
+	responses := roundTrip(t, s,
+		`{"jsonrpc":"2.0","id":1,"method":"resources/list"}`,
+		`not json`,
+	)
+
+	if code := responses[0]["error"].(map[string]any)["code"].(float64); code != codeMethodNotFound {
+		t.Errorf("expected method not found, got %v", code)
+	}
+	if code := responses[1]["error"].(map[string]any)["code"].(float64); code != codeParseError {
+		t.Errorf("expected parse error, got %v", code)
+	}
+}
@@ -0,0 +1,371 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/shaneoxm/recall/internal/dateparse"
+	"github.com/shaneoxm/recall/internal/protocol"
+)
+
+// tool describes an MCP tool and the handler that implements it.
+type tool struct {
+	Name         string         `json:"name"`
+	Description  string         `json:"description"`
+	InputSchema  map[string]any `json:"inputSchema"`
+	OutputSchema map[string]any `json:"outputSchema,omitempty"`
+
+	handler func(ctx context.Context, args json.RawMessage) (any, error)
+}
+
+type addArgs struct {
+	Title    string   `json:"title" description:"Short description of the reminder"`
+	Due      string   `json:"due,omitempty" description:"Due date, e.g. tomorrow, friday, 2024-03-15 or an RFC 3339 timestamp"`
+	Notes    string   `json:"notes,omitempty" description:"Additional context or instructions"`
+	Links    []string `json:"links,omitempty" description:"Related URLs"`
+	Tags     []string `json:"tags,omitempty" description:"Tags used to categorize the reminder"`
+	Priority int      `json:"priority,omitempty" description:"0=none, 1=low, 2=medium, 3=high"`
+	ParentID string   `json:"parent_id,omitempty" description:"ID of the parent reminder when creating a subtask"`
+	Repeat   string   `json:"repeat,omitempty" description:"Repeat rule: daily, weekly, monthly, yearly, weekdays, 'every 2 weeks', 'every mon, fri' or an RFC 5545 RRULE"`
+}
+
+type idArgs struct {
+	ID string `json:"id" description:"Reminder ID as returned by add_reminder or list_reminders"`
+}
+
+type updateArgs struct {
+	ID       string    `json:"id" description:"Reminder ID to update"`
+	Title    *string   `json:"title,omitempty" description:"New title"`
+	Due      *string   `json:"due,omitempty" description:"New due date; same formats as add_reminder"`
+	ClearDue bool      `json:"clear_due,omitempty" description:"Remove the due date"`
+	Notes    *string   `json:"notes,omitempty" description:"Replace the notes"`
+	Links    *[]string `json:"links,omitempty" description:"Replace the links"`
+	Tags     *[]string `json:"tags,omitempty" description:"Replace the tags"`
+	Priority *int      `json:"priority,omitempty" description:"0=none, 1=low, 2=medium, 3=high"`
+	Repeat   *string   `json:"repeat,omitempty" description:"New repeat rule; an empty string stops the reminder repeating"`
+}
+
+type snoozeArgs struct {
+	ID       string `json:"id" description:"Reminder ID to snooze"`
+	Duration string `json:"duration,omitempty" description:"How long to snooze, e.g. 2h, 30m, '1 day'"`
+	Until    string `json:"until,omitempty" description:"Snooze until this time instead; same formats as add_reminder due"`
+}
+
+type listResult struct {
+	Reminders []*protocol.Reminder `json:"reminders"`
+}
+
+type deleteResult struct {
+	ID      string `json:"id"`
+	Deleted bool   `json:"deleted"`
+}
+
+var filterDescriptions = map[string]string{
+	"include_completed": "Include completed reminders",
+	"tags":              "Only reminders with any of these tags",
+	"due_before":        "Only reminders due before this RFC 3339 timestamp",
+	"due_after":         "Only reminders due at or after this RFC 3339 timestamp",
+	"search":            "Only reminders containing this text in title or notes",
+}
+
+func (s *Server) toolset() []*tool {
+	reminderSchema := schemaFor(reflect.TypeOf(protocol.Reminder{}))
+
+	return []*tool{
+		{
+			Name:         "add_reminder",
+			Description:  "Create a new reminder and return it, including its ID.",
+			InputSchema:  schemaFor(reflect.TypeOf(addArgs{})),
+			OutputSchema: reminderSchema,
+			handler:      s.addReminder,
+		},
+		{
+			Name:         "list_reminders",
+			Description:  "List reminders matching an optional filter. Completed reminders are excluded unless include_completed is set.",
+			InputSchema:  describe(schemaFor(reflect.TypeOf(protocol.ListFilter{})), filterDescriptions),
+			OutputSchema: schemaFor(reflect.TypeOf(listResult{})),
+			handler:      s.listReminders,
+		},
+		{
+			Name:         "get_reminder",
+			Description:  "Fetch a single reminder by ID.",
+			InputSchema:  schemaFor(reflect.TypeOf(idArgs{})),
+			OutputSchema: reminderSchema,
+			handler:      s.getReminder,
+		},
+		{
+			Name:         "update_reminder",
+			Description:  "Change fields of an existing reminder. Omitted fields are left unchanged.",
+			InputSchema:  schemaFor(reflect.TypeOf(updateArgs{})),
+			OutputSchema: reminderSchema,
+			handler:      s.updateReminder,
+		},
+		{
+			Name:         "complete_reminder",
+			Description:  "Mark a reminder as completed. Recurring reminders advance to their next occurrence instead.",
+			InputSchema:  schemaFor(reflect.TypeOf(idArgs{})),
+			OutputSchema: reminderSchema,
+			handler:      s.completeReminder,
+		},
+		{
+			Name:         "snooze_reminder",
+			Description:  "Push a reminder's due date forward by a duration or until a given time. Snoozes are counted on the reminder.",
+			InputSchema:  schemaFor(reflect.TypeOf(snoozeArgs{})),
+			OutputSchema: reminderSchema,
+			handler:      s.snoozeReminder,
+		},
+		{
+			Name:         "delete_reminder",
+			Description:  "Permanently delete a reminder.",
+			InputSchema:  schemaFor(reflect.TypeOf(idArgs{})),
+			OutputSchema: schemaFor(reflect.TypeOf(deleteResult{})),
+			handler:      s.deleteReminder,
+		},
+	}
+}
+
+func (s *Server) addReminder(ctx context.Context, raw json.RawMessage) (any, error) {
+	var args addArgs
+	if err := decodeArgs(raw, &args); err != nil {
+		return nil, err
+	}
+	if args.Title == "" {
+		return nil, invalidParams("title is required")
+	}
+
+	reminder := protocol.NewReminder(args.Title)
+	if args.Notes != "" {
+		reminder.SetNotes(args.Notes)
+	}
+	for _, link := range args.Links {
+		reminder.AddLink(link)
+	}
+	for _, tag := range args.Tags {
+		reminder.AddTag(tag)
+	}
+	if args.Priority != 0 {
+		if args.Priority < 0 || args.Priority > 3 {
+			return nil, invalidParams("priority must be between 0 and 3")
+		}
+		reminder.SetPriority(args.Priority)
+	}
+	if args.Due != "" {
+		due, err := s.parseDue(args.Due)
+		if err != nil {
+			return nil, invalidParams("invalid due date: %v", err)
+		}
+		reminder.SetDue(due)
+	}
+	if args.ParentID != "" {
+		reminder.ParentID = args.ParentID
+		reminder.IsSubtask = true
+	}
+	if args.Repeat != "" {
+		rec, err := protocol.ParseRecurrence(args.Repeat)
+		if err != nil {
+			return nil, invalidParams("invalid repeat: %v", err)
+		}
+		reminder.SetRecurrence(rec)
+	}
+
+	if err := s.store.Add(ctx, reminder); err != nil {
+		return nil, fmt.Errorf("saving reminder: %w", err)
+	}
+	return reminder, nil
+}
+
+func (s *Server) listReminders(ctx context.Context, raw json.RawMessage) (any, error) {
+	var filter protocol.ListFilter
+	if err := decodeArgs(raw, &filter); err != nil {
+		return nil, err
+	}
+
+	reminders, err := s.store.List(ctx, &filter)
+	if err != nil {
+		return nil, fmt.Errorf("listing reminders: %w", err)
+	}
+	if reminders == nil {
+		reminders = []*protocol.Reminder{}
+	}
+	return listResult{Reminders: reminders}, nil
+}
+
+func (s *Server) getReminder(ctx context.Context, raw json.RawMessage) (any, error) {
+	var args idArgs
+	if err := decodeID(raw, &args); err != nil {
+		return nil, err
+	}
+
+	reminder, err := s.store.Get(ctx, args.ID)
+	if err != nil {
+		return nil, fmt.Errorf("getting reminder: %w", err)
+	}
+	return reminder, nil
+}
+
+func (s *Server) updateReminder(ctx context.Context, raw json.RawMessage) (any, error) {
+	var args updateArgs
+	if err := decodeArgs(raw, &args); err != nil {
+		return nil, err
+	}
+	if args.ID == "" {
+		return nil, invalidParams("id is required")
+	}
+
+	reminder, err := s.store.Get(ctx, args.ID)
+	if err != nil {
+		return nil, fmt.Errorf("getting reminder: %w", err)
+	}
+
+	if args.Title != nil {
+		reminder.Title = *args.Title
+	}
+	if args.Notes != nil {
+		reminder.Notes = *args.Notes
+	}
+	if args.Links != nil {
+		reminder.Links = *args.Links
+	}
+	if args.Tags != nil {
+		reminder.Tags = *args.Tags
+	}
+	if args.Priority != nil {
+		if *args.Priority < 0 || *args.Priority > 3 {
+			return nil, invalidParams("priority must be between 0 and 3")
+		}
+		reminder.Priority = *args.Priority
+	}
+	if args.ClearDue {
+		reminder.Due = nil
+	} else if args.Due != nil {
+		due, err := s.parseDue(*args.Due)
+		if err != nil {
+			return nil, invalidParams("invalid due date: %v", err)
+		}
+		reminder.Due = &due
+	}
+	if args.Repeat != nil {
+		reminder.Recurrence = nil
+		if *args.Repeat != "" {
+			rec, err := protocol.ParseRecurrence(*args.Repeat)
+			if err != nil {
+				return nil, invalidParams("invalid repeat: %v", err)
+			}
+			reminder.Recurrence = rec
+		}
+	}
+	reminder.UpdatedAt = time.Now()
+
+	if err := s.store.Update(ctx, reminder); err != nil {
+		return nil, fmt.Errorf("updating reminder: %w", err)
+	}
+	return reminder, nil
+}
+
+func (s *Server) completeReminder(ctx context.Context, raw json.RawMessage) (any, error) {
+	var args idArgs
+	if err := decodeID(raw, &args); err != nil {
+		return nil, err
+	}
+
+	if err := s.store.Complete(ctx, args.ID); err != nil {
+		return nil, fmt.Errorf("completing reminder: %w", err)
+	}
+
+	reminder, err := s.store.Get(ctx, args.ID)
+	if err != nil {
+		return nil, fmt.Errorf("getting reminder: %w", err)
+	}
+	return reminder, nil
+}
+
+func (s *Server) snoozeReminder(ctx context.Context, raw json.RawMessage) (any, error) {
+	var args snoozeArgs
+	if err := decodeArgs(raw, &args); err != nil {
+		return nil, err
+	}
+	if args.ID == "" {
+		return nil, invalidParams("id is required")
+	}
+	if (args.Duration == "") == (args.Until == "") {
+		return nil, invalidParams("exactly one of duration or until is required")
+	}
+
+	reminder, err := s.store.Get(ctx, args.ID)
+	if err != nil {
+		return nil, fmt.Errorf("getting reminder: %w", err)
+	}
+
+	now := time.Now()
+	var until time.Time
+	if args.Until != "" {
+		until, err = s.parseDue(args.Until)
+		if err != nil {
+			return nil, invalidParams("invalid until: %v", err)
+		}
+		if !until.After(now) {
+			return nil, invalidParams("until must be in the future")
+		}
+	} else {
+		d, err := dateparse.ParseDuration(args.Duration)
+		if err != nil {
+			return nil, invalidParams("invalid duration: %v", err)
+		}
+		base := now
+		if reminder.Due != nil && reminder.Due.After(now) {
+			base = *reminder.Due
+		}
+		until = base.Add(d)
+	}
+
+	reminder.Snooze(until)
+	if err := s.store.Update(ctx, reminder); err != nil {
+		return nil, fmt.Errorf("updating reminder: %w", err)
+	}
+	return reminder, nil
+}
+
+func (s *Server) deleteReminder(ctx context.Context, raw json.RawMessage) (any, error) {
+	var args idArgs
+	if err := decodeID(raw, &args); err != nil {
+		return nil, err
+	}
+
+	if err := s.store.Delete(ctx, args.ID); err != nil {
+		return nil, fmt.Errorf("deleting reminder: %w", err)
+	}
+	return deleteResult{ID: args.ID, Deleted: true}, nil
+}
+
+func (s *Server) parseDue(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if s.DueParser != nil {
+		return s.DueParser(value)
+	}
+	return time.Time{}, fmt.Errorf("expected an RFC 3339 timestamp, got %q", value)
+}
+
+func decodeArgs(raw json.RawMessage, v any) error {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return invalidParams("invalid arguments: %v", err)
+	}
+	return nil
+}
+
+func decodeID(raw json.RawMessage, args *idArgs) error {
+	if err := decodeArgs(raw, args); err != nil {
+		return err
+	}
+	if args.ID == "" {
+		return invalidParams("id is required")
+	}
+	return nil
+}
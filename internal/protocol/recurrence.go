@@ -0,0 +1,371 @@
+package protocol
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency is the base period of a recurrence rule.
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+// Recurrence is a subset of an RFC 5545 RRULE: FREQ (daily, weekly,
+// monthly, yearly), INTERVAL, BYDAY (daily and weekly only), BYMONTHDAY
+// (monthly and yearly only), COUNT and UNTIL.
+// It is serialized as RRULE text, e.g. "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR".
+type Recurrence struct {
+	Freq       Frequency
+	Interval   int            // 0 or 1 means every period
+	ByDay      []time.Weekday // weekdays the reminder repeats on
+	ByMonthDay int            // day monthly and yearly rules fall on; 0 = the day advanced from
+	Count      int            // occurrences left, including the current one; 0 = unlimited
+	Until      *time.Time     // no occurrences after this time
+}
+
+var rruleDays = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+var weekdays = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+
+// ParseRRule parses RRULE text such as "FREQ=DAILY;COUNT=5".
+// A leading "RRULE:" is accepted.
+func ParseRRule(s string) (*Recurrence, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	if s == "" {
+		return nil, fmt.Errorf("empty recurrence rule")
+	}
+
+	rec := &Recurrence{}
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid rule part %q", part)
+		}
+
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			freq := Frequency(strings.ToUpper(value))
+			switch freq {
+			case Daily, Weekly, Monthly, Yearly:
+				rec.Freq = freq
+			default:
+				return nil, fmt.Errorf("unsupported frequency %q", value)
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid interval %q", value)
+			}
+			rec.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid count %q", value)
+			}
+			rec.Count = n
+		case "UNTIL":
+			until, err := parseUntil(value)
+			if err != nil {
+				return nil, err
+			}
+			rec.Until = &until
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				i := slices.Index(rruleDays, strings.ToUpper(day))
+				if i < 0 {
+					return nil, fmt.Errorf("unsupported BYDAY value %q", day)
+				}
+				rec.ByDay = append(rec.ByDay, time.Weekday(i))
+			}
+		case "BYMONTHDAY":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 || n > 31 {
+				return nil, fmt.Errorf("unsupported BYMONTHDAY value %q", value)
+			}
+			rec.ByMonthDay = n
+		case "WKST":
+			// Weeks always start on Monday.
+		default:
+			return nil, fmt.Errorf("unsupported rule part %q", key)
+		}
+	}
+
+	if rec.Freq == "" {
+		return nil, fmt.Errorf("recurrence rule is missing FREQ")
+	}
+	if len(rec.ByDay) > 0 && rec.Freq != Daily && rec.Freq != Weekly {
+		return nil, fmt.Errorf("BYDAY is only supported with DAILY or WEEKLY")
+	}
+	if len(rec.ByDay) > 0 && rec.Freq == Daily && rec.Interval > 1 {
+		return nil, fmt.Errorf("INTERVAL is not supported with DAILY and BYDAY; use WEEKLY")
+	}
+	if rec.ByMonthDay > 0 && rec.Freq != Monthly && rec.Freq != Yearly {
+		return nil, fmt.Errorf("BYMONTHDAY is only supported with MONTHLY or YEARLY")
+	}
+	if rec.Count > 0 && rec.Until != nil {
+		return nil, fmt.Errorf("COUNT and UNTIL cannot both be set")
+	}
+
+	return rec, nil
+}
+
+func parseUntil(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102T150405", "20060102"} {
+		loc := time.Local
+		if strings.HasSuffix(layout, "Z") {
+			loc = time.UTC
+		}
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			if layout == "20060102" {
+				t = t.Add(24*time.Hour - time.Second) // inclusive of the whole day
+			}
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid until %q", value)
+}
+
+// ParseRecurrence parses RRULE text or a shorthand such as "daily",
+// "weekdays", "every 2 weeks", "every mon, fri" or "every 2 weeks on mon".
+func ParseRecurrence(s string) (*Recurrence, error) {
+	text := strings.ToLower(strings.TrimSpace(s))
+	if strings.HasPrefix(text, "rrule:") || strings.HasPrefix(text, "freq=") {
+		return ParseRRule(strings.ToUpper(strings.TrimSpace(s)))
+	}
+
+	switch text {
+	case "daily":
+		return &Recurrence{Freq: Daily}, nil
+	case "weekly":
+		return &Recurrence{Freq: Weekly}, nil
+	case "monthly":
+		return &Recurrence{Freq: Monthly}, nil
+	case "yearly", "annually":
+		return &Recurrence{Freq: Yearly}, nil
+	case "weekdays", "every weekday", "every workday":
+		return &Recurrence{Freq: Daily, ByDay: slices.Clone(weekdays)}, nil
+	}
+
+	rest, ok := strings.CutPrefix(text, "every ")
+	if !ok {
+		return nil, fmt.Errorf("unrecognized recurrence %q", s)
+	}
+
+	interval := 1
+	if n, unit, ok := strings.Cut(rest, " "); ok {
+		if v, err := strconv.Atoi(n); err == nil && v > 0 {
+			interval, rest = v, unit
+		} else if n == "other" {
+			interval, rest = 2, unit
+		}
+	}
+
+	// "every 2 weeks on mon, fri"
+	if unit, on, ok := strings.Cut(rest, " on "); ok {
+		if strings.TrimSuffix(unit, "s") != "week" {
+			return nil, fmt.Errorf("unrecognized recurrence %q", s)
+		}
+		rest = on
+	} else {
+		switch strings.TrimSuffix(rest, "s") {
+		case "day":
+			return newRecurrence(Daily, interval), nil
+		case "week":
+			return newRecurrence(Weekly, interval), nil
+		case "month":
+			return newRecurrence(Monthly, interval), nil
+		case "year":
+			return newRecurrence(Yearly, interval), nil
+		}
+	}
+
+	// "every mon, fri" / "every monday and thursday"
+	var days []time.Weekday
+	for _, name := range strings.FieldsFunc(strings.ReplaceAll(rest, " and ", ","), func(r rune) bool {
+		return r == ',' || r == ' '
+	}) {
+		day, ok := parseWeekdayName(name)
+		if !ok {
+			return nil, fmt.Errorf("unrecognized recurrence %q", s)
+		}
+		days = append(days, day)
+	}
+	if len(days) == 0 {
+		return nil, fmt.Errorf("unrecognized recurrence %q", s)
+	}
+
+	rec := newRecurrence(Weekly, interval)
+	rec.ByDay = days
+	return rec, nil
+}
+
+func newRecurrence(freq Frequency, interval int) *Recurrence {
+	rec := &Recurrence{Freq: freq}
+	if interval > 1 {
+		rec.Interval = interval
+	}
+	return rec
+}
+
+func parseWeekdayName(name string) (time.Weekday, bool) {
+	name = strings.TrimSuffix(strings.ToLower(name), "s")
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		full := strings.ToLower(d.String())
+		if name == full || name == full[:3] {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// String returns the rule as RRULE text.
+func (r *Recurrence) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, fmt.Sprintf("INTERVAL=%d", r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, d := range r.ByDay {
+			days[i] = rruleDays[d]
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if r.ByMonthDay > 0 {
+		parts = append(parts, fmt.Sprintf("BYMONTHDAY=%d", r.ByMonthDay))
+	}
+	if r.Count > 0 {
+		parts = append(parts, fmt.Sprintf("COUNT=%d", r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Describe returns a short human-readable form such as "every 2 weeks on Mon, Fri".
+func (r *Recurrence) Describe() string {
+	interval := max(r.Interval, 1)
+
+	if r.Freq == Daily && slices.Equal(r.sortedDays(), weekdays) && interval == 1 {
+		return "every weekday"
+	}
+
+	unit := map[Frequency]string{Daily: "day", Weekly: "week", Monthly: "month", Yearly: "year"}[r.Freq]
+	desc := "every " + unit
+	if interval > 1 {
+		desc = fmt.Sprintf("every %d %ss", interval, unit)
+	}
+
+	if len(r.ByDay) > 0 {
+		names := make([]string, len(r.ByDay))
+		for i, d := range r.sortedDays() {
+			names[i] = d.String()[:3]
+		}
+		if interval == 1 && r.Freq == Weekly {
+			desc = "every " + strings.Join(names, ", ")
+		} else {
+			desc += " on " + strings.Join(names, ", ")
+		}
+	}
+	return desc
+}
+
+// sortedDays returns ByDay ordered Monday first.
+func (r *Recurrence) sortedDays() []time.Weekday {
+	days := slices.Clone(r.ByDay)
+	slices.SortFunc(days, func(a, b time.Weekday) int {
+		return (int(a)+6)%7 - (int(b)+6)%7
+	})
+	return days
+}
+
+// Next returns the first occurrence strictly after from, keeping its time
+// of day. Monthly and yearly rules land on ByMonthDay when it is set, so a
+// rule anchored on the 31st returns to it after a shorter month. It
+// reports false when the rule has no further occurrences.
+func (r *Recurrence) Next(from time.Time) (time.Time, bool) {
+	interval := max(r.Interval, 1)
+	var next time.Time
+
+	switch r.Freq {
+	case Daily:
+		next = from.AddDate(0, 0, interval)
+		if len(r.ByDay) > 0 {
+			// ParseRRule rejects INTERVAL here, so every matching day counts.
+			next = from.AddDate(0, 0, 1)
+			for !slices.Contains(r.ByDay, next.Weekday()) {
+				next = next.AddDate(0, 0, 1)
+			}
+		}
+	case Weekly:
+		next = from.AddDate(0, 0, 7*interval)
+		if len(r.ByDay) > 0 {
+			next = r.nextWeekly(from, interval)
+		}
+	case Monthly:
+		next = addMonthsClamped(from, interval, r.monthDay(from))
+	case Yearly:
+		next = addMonthsClamped(from, 12*interval, r.monthDay(from))
+	default:
+		return time.Time{}, false
+	}
+
+	if r.Until != nil && next.After(*r.Until) {
+		return time.Time{}, false
+	}
+	return next, true
+}
+
+// nextWeekly finds the next BYDAY weekday later in the current week, or the
+// first one in the week interval weeks ahead.
+func (r *Recurrence) nextWeekly(from time.Time, interval int) time.Time {
+	offset := (int(from.Weekday()) + 6) % 7 // days since Monday
+	for _, d := range r.sortedDays() {
+		if dayOffset := (int(d) + 6) % 7; dayOffset > offset {
+			return from.AddDate(0, 0, dayOffset-offset)
+		}
+	}
+
+	monday := from.AddDate(0, 0, -offset+7*interval)
+	first := r.sortedDays()[0]
+	return monday.AddDate(0, 0, (int(first)+6)%7)
+}
+
+func (r *Recurrence) monthDay(from time.Time) int {
+	if r.ByMonthDay > 0 {
+		return r.ByMonthDay
+	}
+	return from.Day()
+}
+
+// addMonthsClamped adds months and moves to day, clamping it to the end of
+// the target month instead of overflowing (Jan 31 + 1 month = Feb 28).
+func addMonthsClamped(t time.Time, months, day int) time.Time {
+	first := time.Date(t.Year(), t.Month()+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	lastDay := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(day, lastDay)-1)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (r *Recurrence) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (r *Recurrence) UnmarshalText(data []byte) error {
+	parsed, err := ParseRRule(string(data))
+	if err != nil {
+		return err
+	}
+	*r = *parsed
+	return nil
+}
@@ -0,0 +1,183 @@
+package protocol
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestParseRRule(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"FREQ=DAILY", "FREQ=DAILY"},
+		{"RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR"},
+		{"FREQ=MONTHLY;COUNT=3", "FREQ=MONTHLY;COUNT=3"},
+		{"FREQ=YEARLY;UNTIL=20300101T000000Z", "FREQ=YEARLY;UNTIL=20300101T000000Z"},
+		{"FREQ=DAILY;INTERVAL=1", "FREQ=DAILY"},
+		{"FREQ=MONTHLY;BYMONTHDAY=31", "FREQ=MONTHLY;BYMONTHDAY=31"},
+	}
+
+	for _, tt := range tests {
+		rec, err := ParseRRule(tt.in)
+		if err != nil {
+			t.Errorf("ParseRRule(%q) failed: %v", tt.in, err)
+			continue
+		}
+		if got := rec.String(); got != tt.want {
+			t.Errorf("ParseRRule(%q).String() = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseRRule_Invalid(t *testing.T) {
+	for _, in := range []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=MONTHLY;BYDAY=MO",
+		"FREQ=DAILY;INTERVAL=2;BYDAY=MO,WE,FR",
+		"FREQ=DAILY;COUNT=2;UNTIL=20300101",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=WEEKLY;BYMONTHDAY=1",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=MONTHLY;BYMONTHDAY=1,15",
+	} {
+		if _, err := ParseRRule(in); err == nil {
+			t.Errorf("ParseRRule(%q) expected error", in)
+		}
+	}
+}
+
+func TestParseRecurrence(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"daily", "FREQ=DAILY"},
+		{"weekly", "FREQ=WEEKLY"},
+		{"monthly", "FREQ=MONTHLY"},
+		{"yearly", "FREQ=YEARLY"},
+		{"weekdays", "FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR"},
+		{"every weekday", "FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR"},
+		{"every day", "FREQ=DAILY"},
+		{"every 3 days", "FREQ=DAILY;INTERVAL=3"},
+		{"every other week", "FREQ=WEEKLY;INTERVAL=2"},
+		{"every 2 months", "FREQ=MONTHLY;INTERVAL=2"},
+		{"every mon, fri", "FREQ=WEEKLY;BYDAY=MO,FR"},
+		{"every monday and thursday", "FREQ=WEEKLY;BYDAY=MO,TH"},
+		{"every 2 weeks on mon, wed", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE"},
+		{"freq=weekly;byday=tu", "FREQ=WEEKLY;BYDAY=TU"},
+	}
+
+	for _, tt := range tests {
+		rec, err := ParseRecurrence(tt.in)
+		if err != nil {
+			t.Errorf("ParseRecurrence(%q) failed: %v", tt.in, err)
+			continue
+		}
+		if got := rec.String(); got != tt.want {
+			t.Errorf("ParseRecurrence(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{"sometimes", "every 2 months on mon", "every 2 weeks on day"} {
+		if _, err := ParseRecurrence(in); err == nil {
+			t.Errorf("ParseRecurrence(%q): expected error for unrecognized recurrence", in)
+		}
+	}
+}
+
+func TestRecurrence_Describe(t *testing.T) {
+	tests := []struct {
+		rule string
+		want string
+	}{
+		{"FREQ=DAILY", "every day"},
+		{"FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR", "every weekday"},
+		{"FREQ=WEEKLY;INTERVAL=2", "every 2 weeks"},
+		{"FREQ=WEEKLY;BYDAY=FR,MO", "every Mon, Fri"},
+		{"FREQ=WEEKLY;INTERVAL=2;BYDAY=TU", "every 2 weeks on Tue"},
+		{"FREQ=MONTHLY", "every month"},
+		{"FREQ=YEARLY;INTERVAL=5", "every 5 years"},
+	}
+
+	for _, tt := range tests {
+		rec, _ := ParseRRule(tt.rule)
+		if got := rec.Describe(); got != tt.want {
+			t.Errorf("Describe(%q) = %q, want %q", tt.rule, got, tt.want)
+		}
+	}
+}
+
+func TestRecurrence_Next(t *testing.T) {
+	// Wednesday, Jan 15 2025 09:00
+	from := time.Date(2025, 1, 15, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		rule string
+		from time.Time
+		want time.Time
+	}{
+		{"FREQ=DAILY", from, time.Date(2025, 1, 16, 9, 0, 0, 0, time.UTC)},
+		{"FREQ=DAILY;INTERVAL=3", from, time.Date(2025, 1, 18, 9, 0, 0, 0, time.UTC)},
+		{"FREQ=WEEKLY", from, time.Date(2025, 1, 22, 9, 0, 0, 0, time.UTC)},
+		{"FREQ=WEEKLY;BYDAY=MO,FR", from, time.Date(2025, 1, 17, 9, 0, 0, 0, time.UTC)},
+		{"FREQ=WEEKLY;BYDAY=MO,WE", from, time.Date(2025, 1, 20, 9, 0, 0, 0, time.UTC)},
+		{"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO", from, time.Date(2025, 1, 27, 9, 0, 0, 0, time.UTC)},
+		// Friday -> Monday for weekdays
+		{"FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR", time.Date(2025, 1, 17, 9, 0, 0, 0, time.UTC), time.Date(2025, 1, 20, 9, 0, 0, 0, time.UTC)},
+		{"FREQ=MONTHLY", from, time.Date(2025, 2, 15, 9, 0, 0, 0, time.UTC)},
+		{"FREQ=MONTHLY", time.Date(2025, 1, 31, 9, 0, 0, 0, time.UTC), time.Date(2025, 2, 28, 9, 0, 0, 0, time.UTC)},
+		{"FREQ=YEARLY", time.Date(2024, 2, 29, 9, 0, 0, 0, time.UTC), time.Date(2025, 2, 28, 9, 0, 0, 0, time.UTC)},
+		// BYMONTHDAY returns to the anchor day after a clamped month.
+		{"FREQ=MONTHLY;BYMONTHDAY=31", time.Date(2025, 2, 28, 9, 0, 0, 0, time.UTC), time.Date(2025, 3, 31, 9, 0, 0, 0, time.UTC)},
+		{"FREQ=MONTHLY;BYMONTHDAY=31", time.Date(2025, 3, 31, 9, 0, 0, 0, time.UTC), time.Date(2025, 4, 30, 9, 0, 0, 0, time.UTC)},
+		{"FREQ=YEARLY;BYMONTHDAY=29", time.Date(2027, 2, 28, 9, 0, 0, 0, time.UTC), time.Date(2028, 2, 29, 9, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		rec, err := ParseRRule(tt.rule)
+		if err != nil {
+			t.Fatalf("ParseRRule(%q) failed: %v", tt.rule, err)
+		}
+		got, ok := rec.Next(tt.from)
+		if !ok {
+			t.Errorf("%s: expected a next occurrence", tt.rule)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("%s from %s: got %s, want %s", tt.rule, tt.from.Format(time.RFC1123), got.Format(time.RFC1123), tt.want.Format(time.RFC1123))
+		}
+	}
+}
+
+func TestRecurrence_NextUntil(t *testing.T) {
+	rec, _ := ParseRRule("FREQ=DAILY;UNTIL=20250116")
+	from := time.Date(2025, 1, 16, 9, 0, 0, 0, time.Local)
+
+	if _, ok := rec.Next(from); ok {
+		t.Error("expected no occurrence after UNTIL")
+	}
+}
+
+func TestRecurrence_JSON(t *testing.T) {
+	r := NewReminder("Standup notes")
+	rec, _ := ParseRecurrence("weekdays")
+	r.SetRecurrence(rec)
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+
+	var got Reminder
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	if got.Recurrence == nil || got.Recurrence.String() != rec.String() {
+		t.Errorf("expected recurrence %q to round-trip, got %v", rec, got.Recurrence)
+	}
+}
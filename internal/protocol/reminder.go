@@ -6,25 +6,42 @@ import (
 
 // Reminder represents a reminder with rich context.
 type Reminder struct {
-	ID          string     `json:"id"`
-	Title       string     `json:"title"`
-	Due         *time.Time `json:"due,omitempty"`
-	Notes       string     `json:"notes,omitempty"`
-	Links       []string   `json:"links,omitempty"`
-	Tags        []string   `json:"tags,omitempty"`
-	Priority    int        `json:"priority,omitempty"` // 0=none, 1=low, 2=medium, 3=high
-	Completed   bool       `json:"completed"`
-	CompletedAt *time.Time `json:"completed_at,omitempty"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
+	ID          string     `json:"id" yaml:"id"`
+	Title       string     `json:"title" yaml:"title"`
+	Due         *time.Time `json:"due,omitempty" yaml:"due,omitempty"`
+	Notes       string     `json:"notes,omitempty" yaml:"notes,omitempty"`
+	Links       []string   `json:"links,omitempty" yaml:"links,omitempty"`
+	Tags        []string   `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Priority    int        `json:"priority,omitempty" yaml:"priority,omitempty"` // 0=none, 1=low, 2=medium, 3=high
+	Completed   bool       `json:"completed" yaml:"completed"`
+	CompletedAt *time.Time `json:"completed_at,omitempty" yaml:"completed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at" yaml:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" yaml:"updated_at"`
 
 	// ParentID is the ID of the parent task (for subtasks)
-	ParentID string `json:"parent_id,omitempty"`
+	ParentID string `json:"parent_id,omitempty" yaml:"parent_id,omitempty"`
 
 	// IsSubtask indicates if this reminder is a subtask
-	IsSubtask bool `json:"is_subtask,omitempty"`
+	IsSubtask bool `json:"is_subtask,omitempty" yaml:"is_subtask,omitempty"`
+
+	// Recurrence makes the reminder repeat; completing it advances Due to
+	// the next occurrence instead of closing it.
+	Recurrence *Recurrence `json:"recurrence,omitempty" yaml:"recurrence,omitempty"`
+
+	// SnoozeCount is how many times the reminder has been snoozed.
+	SnoozeCount int `json:"snooze_count,omitempty" yaml:"snooze_count,omitempty"`
+
+	// LastSnoozedAt is when the reminder was last snoozed.
+	LastSnoozedAt *time.Time `json:"last_snoozed_at,omitempty" yaml:"last_snoozed_at,omitempty"`
+
+	// Metadata holds backend-specific details such as a Todoist project ID.
+	Metadata map[string]string `json:"metadata,omitempty" yaml:"metadata,omitempty"`
 }
 
+// ChronicSnoozeThreshold is the snooze count at which a reminder is
+// considered chronically deferred.
+const ChronicSnoozeThreshold = 3
+
 // NewReminder creates a new reminder with the given title.
 func NewReminder(title string) *Reminder {
 	now := time.Now()
@@ -36,12 +53,74 @@ func NewReminder(title string) *Reminder {
 	}
 }
 
-// Complete marks the reminder as completed.
+// Complete marks the reminder as completed. A recurring reminder instead
+// rolls forward to its next occurrence after now and stays open; it is only
+// closed once its rule runs out of occurrences.
 func (r *Reminder) Complete() {
 	now := time.Now()
+	r.UpdatedAt = now
+
+	if r.Recurrence != nil && r.advance(now) {
+		return
+	}
+
 	r.Completed = true
 	r.CompletedAt = &now
-	r.UpdatedAt = now
+}
+
+// advance moves Due to the next occurrence after now. Occurrences already
+// past count as used, so a rule with a COUNT completed late still ends on
+// time. It reports false when the recurrence has no occurrences left.
+func (r *Reminder) advance(now time.Time) bool {
+	r.anchorRecurrence()
+	rec := r.Recurrence
+	if rec.Count == 1 {
+		return false
+	}
+
+	from := now
+	if r.Due != nil {
+		from = *r.Due
+	}
+
+	next, ok := rec.Next(from)
+	used := 1 // the occurrence being completed
+	for ok && !next.After(now) {
+		next, ok = rec.Next(next)
+		used++
+	}
+	if !ok {
+		return false
+	}
+
+	if rec.Count > 0 {
+		if rec.Count-used < 1 {
+			return false
+		}
+		rec.Count -= used
+	}
+	r.Due = &next
+	return true
+}
+
+// SetRecurrence makes the reminder repeat according to rec; nil stops it repeating.
+func (r *Reminder) SetRecurrence(rec *Recurrence) {
+	r.Recurrence = rec
+	r.anchorRecurrence()
+	r.UpdatedAt = time.Now()
+}
+
+// anchorRecurrence pins a monthly or yearly rule to the day of the month
+// it starts on, so clamping to a short month does not carry over: a rule
+// due Jan 31 falls on Feb 28 and then Mar 31.
+func (r *Reminder) anchorRecurrence() {
+	rec := r.Recurrence
+	if rec == nil || r.Due == nil || rec.ByMonthDay > 0 {
+		return
+	}
+	if rec.Freq == Monthly || rec.Freq == Yearly {
+		rec.ByMonthDay = r.Due.Day()
+	}
 }
 
 // AddLink adds a link to the reminder.
@@ -73,3 +152,18 @@ func (r *Reminder) SetPriority(priority int) {
 	r.Priority = priority
 	r.UpdatedAt = time.Now()
 }
+
+// Snooze pushes the due date to until and records the snooze.
+func (r *Reminder) Snooze(until time.Time) {
+	now := time.Now()
+	r.Due = &until
+	r.SnoozeCount++
+	r.LastSnoozedAt = &now
+	r.UpdatedAt = now
+}
+
+// ChronicallySnoozed reports whether the reminder has been snoozed at least
+// ChronicSnoozeThreshold times.
+func (r *Reminder) ChronicallySnoozed() bool {
+	return r.SnoozeCount >= ChronicSnoozeThreshold
+}
@@ -130,3 +130,134 @@ func TestGenerateID_Unique(t *testing.T) {
 		ids[id] = true
 	}
 }
+
+func TestReminder_CompleteRecurring(t *testing.T) {
+	r := NewReminder("Pay rent")
+	due := time.Now().Add(time.Hour)
+	r.SetDue(due)
+	r.SetRecurrence(&Recurrence{Freq: Monthly})
+
+	r.Complete()
+
+	if r.Completed {
+		t.Error("expected recurring reminder to stay open")
+	}
+	if r.Due == nil || !r.Due.Equal(addMonthsClamped(due, 1, due.Day())) {
+		t.Errorf("expected due to roll forward one month, got %v", r.Due)
+	}
+}
+
+func TestReminder_CompleteRecurringMonthEnd(t *testing.T) {
+	year := time.Now().Year() + 1
+	r := NewReminder("Pay rent")
+	r.SetDue(time.Date(year, time.January, 31, 9, 0, 0, 0, time.Local))
+	r.SetRecurrence(&Recurrence{Freq: Monthly})
+
+	febEnd := time.Date(year, time.March, 0, 9, 0, 0, 0, time.Local)
+	for _, want := range []time.Time{
+		febEnd,
+		time.Date(year, time.March, 31, 9, 0, 0, 0, time.Local),
+		time.Date(year, time.April, 30, 9, 0, 0, 0, time.Local),
+		time.Date(year, time.May, 31, 9, 0, 0, 0, time.Local),
+	} {
+		r.Complete()
+		if r.Due == nil || !r.Due.Equal(want) {
+			t.Fatalf("expected due %s, got %v", want.Format(time.DateOnly), r.Due)
+		}
+	}
+}
+
+func TestReminder_CompleteRecurringLeapDay(t *testing.T) {
+	year := time.Now().Year() + 1
+	for year%4 != 0 || (year%100 == 0 && year%400 != 0) {
+		year++
+	}
+	r := NewReminder("Birthday")
+	r.SetDue(time.Date(year, time.February, 29, 9, 0, 0, 0, time.Local))
+	r.SetRecurrence(&Recurrence{Freq: Yearly})
+
+	for i := 1; i <= 4; i++ {
+		r.Complete()
+	}
+	if want := time.Date(year+4, time.February, 29, 9, 0, 0, 0, time.Local); !r.Due.Equal(want) {
+		t.Errorf("expected due back on %s, got %v", want.Format(time.DateOnly), r.Due)
+	}
+}
+
+func TestReminder_CompleteRecurringSkipsPast(t *testing.T) {
+	r := NewReminder("Water plants")
+	r.SetDue(time.Now().AddDate(0, 0, -10))
+	r.SetRecurrence(&Recurrence{Freq: Daily})
+
+	r.Complete()
+
+	if r.Due == nil || !r.Due.After(time.Now()) {
+		t.Errorf("expected next occurrence in the future, got %v", r.Due)
+	}
+}
+
+func TestReminder_CompleteRecurringCount(t *testing.T) {
+	r := NewReminder("Take medication")
+	r.SetDue(time.Now())
+	r.SetRecurrence(&Recurrence{Freq: Daily, Count: 2})
+
+	r.Complete()
+	if r.Completed {
+		t.Fatal("expected first completion to roll forward")
+	}
+	if r.Recurrence.Count != 1 {
+		t.Errorf("expected 1 occurrence left, got %d", r.Recurrence.Count)
+	}
+
+	r.Complete()
+	if !r.Completed {
+		t.Error("expected reminder to close after its last occurrence")
+	}
+}
+
+func TestReminder_CompleteRecurringCountSkipsPast(t *testing.T) {
+	now := time.Now()
+
+	// Completed two days late: the two missed occurrences are used up.
+	r := NewReminder("Take medication")
+	r.SetDue(now.AddDate(0, 0, -2).Add(-time.Hour))
+	r.SetRecurrence(&Recurrence{Freq: Daily, Count: 5})
+	r.Complete()
+	if r.Completed || r.Recurrence.Count != 2 {
+		t.Errorf("expected 2 occurrences left, got completed=%t count=%d", r.Completed, r.Recurrence.Count)
+	}
+
+	// Completed after the last occurrence has passed: the rule is over.
+	r = NewReminder("Take medication")
+	r.SetDue(now.AddDate(0, 0, -10))
+	r.SetRecurrence(&Recurrence{Freq: Daily, Count: 5})
+	r.Complete()
+	if !r.Completed {
+		t.Errorf("expected reminder to close, got due %v with count %d", r.Due, r.Recurrence.Count)
+	}
+}
+
+func TestReminder_Snooze(t *testing.T) {
+	r := NewReminder("Test")
+	until := time.Now().Add(2 * time.Hour)
+
+	for i := 0; i < ChronicSnoozeThreshold; i++ {
+		if r.ChronicallySnoozed() {
+			t.Fatalf("expected not chronically snoozed after %d snoozes", i)
+		}
+		r.Snooze(until)
+	}
+
+	if r.Due == nil || !r.Due.Equal(until) {
+		t.Errorf("expected due %v, got %v", until, r.Due)
+	}
+	if r.SnoozeCount != ChronicSnoozeThreshold {
+		t.Errorf("expected snooze count %d, got %d", ChronicSnoozeThreshold, r.SnoozeCount)
+	}
+	if r.LastSnoozedAt == nil {
+		t.Error("expected LastSnoozedAt to be set")
+	}
+	if !r.ChronicallySnoozed() {
+		t.Error("expected reminder to be chronically snoozed")
+	}
+}
@@ -2,9 +2,19 @@ package protocol
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"time"
 )
 
+// ErrNotFound is returned (possibly wrapped) by stores when a reminder
+// does not exist.
+var ErrNotFound = errors.New("not found")
+
+// ErrInvalid is returned (possibly wrapped) by stores when a reminder has
+// a value the backend cannot store.
+var ErrInvalid = errors.New("invalid argument")
+
 // Store defines the interface for reminder storage backends.
 type Store interface {
 	// Add creates a new reminder.
@@ -22,24 +32,169 @@ type Store interface {
 	// Delete removes a reminder by ID.
 	Delete(ctx context.Context, id string) error
 
-	// Complete marks a reminder as completed.
+	// Complete marks a reminder as completed. Recurring reminders advance
+	// to their next occurrence instead of closing.
 	Complete(ctx context.Context, id string) error
 }
 
+// Streamer is implemented by stores that can deliver List results as they
+// arrive, e.g. page by page from a remote API. fn is called once per
+// matching reminder; returning an error stops the listing and is passed
+// back to the caller.
+type Streamer interface {
+	ListEach(ctx context.Context, filter *ListFilter, fn func(*Reminder) error) error
+}
+
+// Each calls fn for every reminder in s matching filter, streaming when s
+// implements Streamer.
+func Each(ctx context.Context, s Store, filter *ListFilter, fn func(*Reminder) error) error {
+	if st, ok := s.(Streamer); ok {
+		return st.ListEach(ctx, filter, fn)
+	}
+
+	reminders, err := s.List(ctx, filter)
+	if err != nil {
+		return err
+	}
+	for _, r := range reminders {
+		if err := fn(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // ListFilter specifies criteria for listing reminders.
 type ListFilter struct {
 	// IncludeCompleted includes completed reminders in results.
-	IncludeCompleted bool
+	IncludeCompleted bool `json:"include_completed,omitempty"`
 
 	// Tags filters to reminders with any of these tags.
-	Tags []string
+	Tags []string `json:"tags,omitempty"`
 
-	// DueBefore filters to reminders due before this time.
-	DueBefore *time.Time
+	// DueBefore filters to reminders due strictly before this time.
+	DueBefore *time.Time `json:"due_before,omitempty"`
 
-	// DueAfter filters to reminders due after this time.
-	DueAfter *time.Time
+	// DueAfter filters to reminders due at or after this time.
+	DueAfter *time.Time `json:"due_after,omitempty"`
 
 	// Search filters to reminders containing this text in title or notes.
-	Search string
+	Search string `json:"search,omitempty"`
+}
+
+// Matches reports whether r passes the filter. A nil filter matches
+// everything. Stores that filter client-side use this so every backend
+// agrees on the semantics:
+//   - tags match case-insensitively, and any one tag is enough
+//   - search is a case-insensitive substring of the title or notes
+//   - due ranges are half-open, [DueAfter, DueBefore)
+//   - when a due range is set, reminders without a due date are excluded;
+//     undated subtasks are listed with their parent instead (see Apply)
+func (f *ListFilter) Matches(r *Reminder) bool {
+	return f.matches(r, true)
+}
+
+// MatchesWithParent reports whether r is an undated subtask that fails
+// Matches only for lacking a due date. Such a subtask is listed when its
+// parent is, so stores that stream results hold it back until the end
+// and pass it to ListedSubtasks.
+func (f *ListFilter) MatchesWithParent(r *Reminder) bool {
+	return f != nil && r.IsSubtask && r.Due == nil && f.hasDueRange() && f.matches(r, false)
+}
+
+// Apply returns the reminders in all that pass the filter, in order,
+// including undated subtasks whose parent passes.
+func (f *ListFilter) Apply(all []*Reminder) []*Reminder {
+	listed := map[string]bool{}
+	var held []*Reminder
+	for _, r := range all {
+		if f.Matches(r) {
+			listed[r.ID] = true
+		} else if f.MatchesWithParent(r) {
+			held = append(held, r)
+		}
+	}
+	for _, r := range ListedSubtasks(held, listed) {
+		listed[r.ID] = true
+	}
+
+	var result []*Reminder
+	for _, r := range all {
+		if listed[r.ID] {
+			result = append(result, r)
+		}
+	}
+	return result
+}
+
+// ListedSubtasks returns the subtasks in held whose parent is in listed,
+// adding each to listed so that their own subtasks follow.
+func ListedSubtasks(held []*Reminder, listed map[string]bool) []*Reminder {
+	var result []*Reminder
+	for found := true; found; {
+		found = false
+		rest := held[:0:0]
+		for _, r := range held {
+			if listed[r.ParentID] {
+				listed[r.ID] = true
+				result = append(result, r)
+				found = true
+			} else {
+				rest = append(rest, r)
+			}
+		}
+		held = rest
+	}
+	return result
+}
+
+func (f *ListFilter) hasDueRange() bool {
+	return f.DueBefore != nil || f.DueAfter != nil
+}
+
+func (f *ListFilter) matches(r *Reminder, checkDue bool) bool {
+	if f == nil {
+		return true
+	}
+
+	if !f.IncludeCompleted && r.Completed {
+		return false
+	}
+
+	if len(f.Tags) > 0 && !hasAnyTag(r, f.Tags) {
+		return false
+	}
+
+	if f.Search != "" {
+		search := strings.ToLower(f.Search)
+		if !strings.Contains(strings.ToLower(r.Title), search) &&
+			!strings.Contains(strings.ToLower(r.Notes), search) {
+			return false
+		}
+	}
+
+	if checkDue && f.hasDueRange() {
+		if r.Due == nil {
+			return false
+		}
+		if f.DueAfter != nil && r.Due.Before(*f.DueAfter) {
+			return false
+		}
+		if f.DueBefore != nil && !r.Due.Before(*f.DueBefore) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func hasAnyTag(r *Reminder, tags []string) bool {
+	for _, want := range tags {
+		for _, tag := range r.Tags {
+			if strings.EqualFold(tag, want) {
+				return true
+			}
+		}
+	}
+	return false
 }
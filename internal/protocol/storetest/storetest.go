@@ -0,0 +1,351 @@
+// Package storetest provides a conformance suite for protocol.Store
+// implementations. Adapters run it from their own tests against a real
+// store or a local fake:
+//
+//	func TestConformance(t *testing.T) {
+//		storetest.RunConformance(t, func(t *testing.T) protocol.Store {
+//			s, _ := jsonl.New(filepath.Join(t.TempDir(), "reminders.jsonl"))
+//			return s
+//		})
+//	}
+package storetest
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/shaneoxm/recall/internal/protocol"
+)
+
+// Factory returns a new, empty store. It is called once per subtest.
+type Factory func(t *testing.T) protocol.Store
+
+// Option configures RunConformance.
+type Option func(*suite)
+
+type suite struct {
+	skip []string
+}
+
+// Skip leaves out the named tests, such as "Subtasks", for features a
+// backend cannot offer.
+func Skip(names ...string) Option {
+	return func(s *suite) {
+		s.skip = append(s.skip, names...)
+	}
+}
+
+// RunConformance runs the Store conformance suite as subtests of t.
+func RunConformance(t *testing.T, factory Factory, opts ...Option) {
+	var cfg suite
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, s protocol.Store)
+	}{
+		{"AddGet", testAddGet},
+		{"GetNotFound", testGetNotFound},
+		{"Update", testUpdate},
+		{"UpdateNotFound", testUpdateNotFound},
+		{"Delete", testDelete},
+		{"DeleteNotFound", testDeleteNotFound},
+		{"Complete", testComplete},
+		{"CompleteNotFound", testCompleteNotFound},
+		{"ListNilFilter", testListNilFilter},
+		{"FilterCompleted", testFilterCompleted},
+		{"FilterTags", testFilterTags},
+		{"FilterSearch", testFilterSearch},
+		{"FilterDueRange", testFilterDueRange},
+		{"Subtasks", testSubtasks},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if slices.Contains(cfg.skip, tt.name) {
+				t.Skip("not supported by this backend")
+			}
+			tt.fn(t, factory(t))
+		})
+	}
+}
+
+// due returns a whole-minute time so backends that drop seconds still
+// round-trip it exactly.
+func due(days int, hour int) time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day()+days, hour, 0, 0, 0, time.Local)
+}
+
+func add(t *testing.T, s protocol.Store, r *protocol.Reminder) *protocol.Reminder {
+	t.Helper()
+	if err := s.Add(context.Background(), r); err != nil {
+		t.Fatalf("Add(%q) failed: %v", r.Title, err)
+	}
+	if r.ID == "" {
+		t.Fatalf("Add(%q) left the ID empty", r.Title)
+	}
+	return r
+}
+
+func get(t *testing.T, s protocol.Store, id string) *protocol.Reminder {
+	t.Helper()
+	r, err := s.Get(context.Background(), id)
+	if err != nil {
+		t.Fatalf("Get(%q) failed: %v", id, err)
+	}
+	return r
+}
+
+func titles(t *testing.T, s protocol.Store, filter *protocol.ListFilter) []string {
+	t.Helper()
+	reminders, err := s.List(context.Background(), filter)
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	var out []string
+	for _, r := range reminders {
+		out = append(out, r.Title)
+	}
+	slices.Sort(out)
+	return out
+}
+
+func expectTitles(t *testing.T, s protocol.Store, filter *protocol.ListFilter, want ...string) {
+	t.Helper()
+	slices.Sort(want)
+	if got := titles(t, s, filter); !slices.Equal(got, want) {
+		t.Errorf("List(%+v) = %q, want %q", filter, got, want)
+	}
+}
+
+func expectNotFound(t *testing.T, op string, err error) {
+	t.Helper()
+	if !errors.Is(err, protocol.ErrNotFound) {
+		t.Errorf("%s: expected protocol.ErrNotFound, got %v", op, err)
+	}
+}
+
+func testAddGet(t *testing.T, s protocol.Store) {
+	r := protocol.NewReminder("Review PR")
+	r.SetNotes("Check the error handling")
+	r.AddLink("https://github.com/shaneoxm/recall/pull/1")
+	r.AddTag("work")
+	r.AddTag("code")
+	r.SetPriority(2)
+	r.SetDue(due(1, 9))
+	add(t, s, r)
+
+	got := get(t, s, r.ID)
+	if got.ID != r.ID {
+		t.Errorf("expected ID %q, got %q", r.ID, got.ID)
+	}
+	if got.Title != r.Title {
+		t.Errorf("expected title %q, got %q", r.Title, got.Title)
+	}
+	if got.Notes != r.Notes {
+		t.Errorf("expected notes %q, got %q", r.Notes, got.Notes)
+	}
+	if !slices.Equal(got.Links, r.Links) {
+		t.Errorf("expected links %q, got %q", r.Links, got.Links)
+	}
+	gotTags := slices.Sorted(slices.Values(got.Tags))
+	if want := []string{"code", "work"}; !slices.Equal(gotTags, want) {
+		t.Errorf("expected tags %q, got %q", want, gotTags)
+	}
+	if got.Priority != 2 {
+		t.Errorf("expected priority 2, got %d", got.Priority)
+	}
+	if got.Due == nil || !got.Due.Equal(*r.Due) {
+		t.Errorf("expected due %v, got %v", r.Due, got.Due)
+	}
+	if got.Completed {
+		t.Error("expected new reminder to be open")
+	}
+}
+
+func testGetNotFound(t *testing.T, s protocol.Store) {
+	_, err := s.Get(context.Background(), "does-not-exist")
+	expectNotFound(t, "Get", err)
+}
+
+func testUpdate(t *testing.T, s protocol.Store) {
+	r := add(t, s, protocol.NewReminder("Call mom"))
+
+	r.SetNotes("Birthday next week")
+	r.SetPriority(3)
+	r.SetDue(due(2, 18))
+	if err := s.Update(context.Background(), r); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	got := get(t, s, r.ID)
+	if got.Notes != "Birthday next week" {
+		t.Errorf("expected notes to be updated, got %q", got.Notes)
+	}
+	if got.Priority != 3 {
+		t.Errorf("expected priority 3, got %d", got.Priority)
+	}
+	if got.Due == nil || !got.Due.Equal(*r.Due) {
+		t.Errorf("expected due %v, got %v", r.Due, got.Due)
+	}
+	expectTitles(t, s, nil, "Call mom")
+}
+
+func testUpdateNotFound(t *testing.T, s protocol.Store) {
+	r := protocol.NewReminder("Ghost")
+	r.ID = "does-not-exist"
+	expectNotFound(t, "Update", s.Update(context.Background(), r))
+}
+
+func testDelete(t *testing.T, s protocol.Store) {
+	keep := add(t, s, protocol.NewReminder("Keep"))
+	drop := add(t, s, protocol.NewReminder("Drop"))
+
+	if err := s.Delete(context.Background(), drop.ID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	_, err := s.Get(context.Background(), drop.ID)
+	expectNotFound(t, "Get after Delete", err)
+	get(t, s, keep.ID)
+	expectTitles(t, s, nil, "Keep")
+}
+
+func testDeleteNotFound(t *testing.T, s protocol.Store) {
+	expectNotFound(t, "Delete", s.Delete(context.Background(), "does-not-exist"))
+}
+
+func testComplete(t *testing.T, s protocol.Store) {
+	r := add(t, s, protocol.NewReminder("Water plants"))
+
+	if err := s.Complete(context.Background(), r.ID); err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+
+	if got := get(t, s, r.ID); !got.Completed {
+		t.Error("expected reminder to be completed")
+	}
+}
+
+func testCompleteNotFound(t *testing.T, s protocol.Store) {
+	expectNotFound(t, "Complete", s.Complete(context.Background(), "does-not-exist"))
+}
+
+func testListNilFilter(t *testing.T, s protocol.Store) {
+	expectTitles(t, s, nil)
+
+	add(t, s, protocol.NewReminder("First"))
+	add(t, s, protocol.NewReminder("Second"))
+	expectTitles(t, s, nil, "First", "Second")
+}
+
+func testFilterCompleted(t *testing.T, s protocol.Store) {
+	add(t, s, protocol.NewReminder("Open"))
+	done := add(t, s, protocol.NewReminder("Done"))
+	if err := s.Complete(context.Background(), done.ID); err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+
+	expectTitles(t, s, &protocol.ListFilter{}, "Open")
+	expectTitles(t, s, &protocol.ListFilter{IncludeCompleted: true}, "Done", "Open")
+}
+
+func testFilterTags(t *testing.T, s protocol.Store) {
+	work := protocol.NewReminder("Work item")
+	work.AddTag("work")
+	home := protocol.NewReminder("Home item")
+	home.AddTag("home")
+	both := protocol.NewReminder("Both")
+	both.AddTag("work")
+	both.AddTag("home")
+	for _, r := range []*protocol.Reminder{work, home, both, protocol.NewReminder("Untagged")} {
+		add(t, s, r)
+	}
+
+	expectTitles(t, s, &protocol.ListFilter{Tags: []string{"work"}}, "Work item", "Both")
+	expectTitles(t, s, &protocol.ListFilter{Tags: []string{"WORK"}}, "Work item", "Both")
+	expectTitles(t, s, &protocol.ListFilter{Tags: []string{"work", "home"}}, "Work item", "Home item", "Both")
+	expectTitles(t, s, &protocol.ListFilter{Tags: []string{"garden"}})
+}
+
+func testFilterSearch(t *testing.T, s protocol.Store) {
+	dentist := protocol.NewReminder("Call the dentist")
+	insurance := protocol.NewReminder("Renew insurance")
+	insurance.SetNotes("Ask the dentist office for the claim form")
+	add(t, s, dentist)
+	add(t, s, insurance)
+	add(t, s, protocol.NewReminder("Buy milk"))
+
+	expectTitles(t, s, &protocol.ListFilter{Search: "DENTIST"}, "Call the dentist", "Renew insurance")
+	expectTitles(t, s, &protocol.ListFilter{Search: "milk"}, "Buy milk")
+	expectTitles(t, s, &protocol.ListFilter{Search: "nothing like this"})
+}
+
+func testFilterDueRange(t *testing.T, s protocol.Store) {
+	for _, tc := range []struct {
+		title string
+		due   time.Time
+	}{
+		{"Yesterday", due(-1, 9)},
+		{"Today morning", due(0, 9)},
+		{"Today evening", due(0, 18)},
+		{"Tomorrow", due(1, 9)},
+		{"Next week", due(7, 9)},
+	} {
+		r := protocol.NewReminder(tc.title)
+		r.SetDue(tc.due)
+		add(t, s, r)
+	}
+	add(t, s, protocol.NewReminder("Someday"))
+
+	today, tomorrow := due(0, 0), due(1, 0)
+	expectTitles(t, s, &protocol.ListFilter{DueAfter: &today, DueBefore: &tomorrow}, "Today morning", "Today evening")
+	expectTitles(t, s, &protocol.ListFilter{DueBefore: &today}, "Yesterday")
+	expectTitles(t, s, &protocol.ListFilter{DueAfter: &tomorrow}, "Tomorrow", "Next week")
+
+	// DueAfter is inclusive, DueBefore exclusive.
+	morning := due(0, 9)
+	expectTitles(t, s, &protocol.ListFilter{DueAfter: &morning, DueBefore: &tomorrow}, "Today morning", "Today evening")
+	expectTitles(t, s, &protocol.ListFilter{DueAfter: &today, DueBefore: &morning})
+}
+
+func testSubtasks(t *testing.T, s protocol.Store) {
+	parent := protocol.NewReminder("Plan trip")
+	parent.SetDue(due(0, 12))
+	add(t, s, parent)
+
+	child := protocol.NewReminder("Book flights")
+	child.ParentID = parent.ID
+	child.IsSubtask = true
+	add(t, s, child)
+
+	got := get(t, s, child.ID)
+	if got.ParentID != parent.ID {
+		t.Errorf("expected parent %q, got %q", parent.ID, got.ParentID)
+	}
+	if !got.IsSubtask {
+		t.Error("expected IsSubtask to be set")
+	}
+
+	later := protocol.NewReminder("Move house")
+	later.SetDue(due(30, 12))
+	add(t, s, later)
+
+	other := protocol.NewReminder("Hire movers")
+	other.ParentID = later.ID
+	other.IsSubtask = true
+	add(t, s, other)
+
+	// Undated subtasks stay with their parent in due-filtered listings,
+	// and are left out with it.
+	today, tomorrow := due(0, 0), due(1, 0)
+	expectTitles(t, s, &protocol.ListFilter{DueAfter: &today, DueBefore: &tomorrow}, "Plan trip", "Book flights")
+	expectTitles(t, s, &protocol.ListFilter{DueAfter: &tomorrow}, "Move house", "Hire movers")
+	expectTitles(t, s, &protocol.ListFilter{DueBefore: &today})
+}
@@ -0,0 +1,106 @@
+package syncer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// State is the persisted mapping between reminders in two backends.
+type State struct {
+	path  string
+	Links []*Link `json:"links"`
+}
+
+// Link pairs the same reminder in two backends.
+type Link struct {
+	// Items holds what each backend looked like at the last sync, keyed
+	// by backend name.
+	Items    map[string]Item `json:"items"`
+	SyncedAt time.Time       `json:"synced_at"`
+
+	// DeletedIn names the backend the reminder was deleted from. The link
+	// stays behind as a tombstone so the copy left in the other backend
+	// is not copied back, and is dropped once that copy is gone too.
+	DeletedIn string `json:"deleted_in,omitempty"`
+}
+
+// Item is one side of a link as of the last sync.
+type Item struct {
+	ID        string    `json:"id"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+
+	// Hash fingerprints the synced fields so changes can be detected on
+	// backends that do not track modification times.
+	Hash string `json:"hash"`
+}
+
+// StatePath returns the state file for a pair of backends. The order of
+// the names does not matter, so one-way syncs in either direction and
+// bidirectional syncs share a mapping.
+func StatePath(dir, a, b string) string {
+	if b < a {
+		a, b = b, a
+	}
+	return filepath.Join(dir, "sync", a+"-"+b+".json")
+}
+
+// LoadState reads the state at path. A missing file yields empty state.
+func LoadState(path string) (*State, error) {
+	state := &State{path: path}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading sync state: %w", err)
+	}
+
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("parsing sync state %s: %w", path, err)
+	}
+	return state, nil
+}
+
+// Save writes the state back to the path it was loaded from.
+func (s *State) Save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("creating directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling sync state: %w", err)
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("writing sync state: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("replacing sync state: %w", err)
+	}
+	return nil
+}
+
+// Lookup returns the link holding the reminder id in the named backend.
+func (s *State) Lookup(backend, id string) *Link {
+	for _, l := range s.Links {
+		if item, ok := l.Items[backend]; ok && item.ID == id {
+			return l
+		}
+	}
+	return nil
+}
+
+func (s *State) remove(link *Link) {
+	for i, l := range s.Links {
+		if l == link {
+			s.Links = append(s.Links[:i], s.Links[i+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,386 @@
+// Package syncer copies reminders between two protocol.Store backends.
+//
+// A persisted State maps reminder IDs in one backend to IDs in the other
+// and remembers a fingerprint of each side as of the last sync. Comparing
+// fingerprints tells which side changed since then; when both did, the
+// pair is reported as a conflict and left alone. When one side is deleted
+// the pair is kept as a tombstone, so the other side is not copied back.
+package syncer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/shaneoxm/recall/internal/protocol"
+)
+
+// Endpoint is a named backend taking part in a sync.
+type Endpoint struct {
+	Name  string
+	Store protocol.Store
+}
+
+// Syncer plans and applies a sync from one backend to another.
+type Syncer struct {
+	From  Endpoint
+	To    Endpoint
+	State *State
+
+	// Both also copies changes and new reminders from To back to From.
+	Both bool
+
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// ActionKind is what a sync action does.
+type ActionKind string
+
+const (
+	ActionCreate   ActionKind = "create"
+	ActionUpdate   ActionKind = "update"
+	ActionComplete ActionKind = "complete"
+	ActionConflict ActionKind = "conflict"
+	ActionUnlink   ActionKind = "unlink"
+)
+
+// Action is a single planned change.
+type Action struct {
+	Kind   ActionKind `json:"action" yaml:"action"`
+	From   string     `json:"from" yaml:"from"`
+	To     string     `json:"to" yaml:"to"`
+	Title  string     `json:"title" yaml:"title"`
+	FromID string     `json:"from_id" yaml:"from_id"`
+	ToID   string     `json:"to_id,omitempty" yaml:"to_id,omitempty"`
+	Reason string     `json:"reason,omitempty" yaml:"reason,omitempty"`
+
+	// Error is set by Apply when the action failed.
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+
+	from, to Endpoint
+	source   *protocol.Reminder
+	target   *protocol.Reminder
+	link     *Link
+
+	// deletedIn is set on an unlink that leaves a tombstone.
+	deletedIn string
+}
+
+// Plan is the list of actions a sync would perform.
+type Plan struct {
+	Actions []*Action `json:"actions" yaml:"actions"`
+}
+
+// Count returns how many actions of the given kind the plan holds.
+func (p *Plan) Count(kind ActionKind) int {
+	n := 0
+	for _, a := range p.Actions {
+		if a.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+// Failed returns the actions that Apply could not perform.
+func (p *Plan) Failed() []*Action {
+	var failed []*Action
+	for _, a := range p.Actions {
+		if a.Error != "" {
+			failed = append(failed, a)
+		}
+	}
+	return failed
+}
+
+// Plan compares both backends against the saved state and returns the
+// actions needed to bring them in line. Nothing is written.
+func (s *Syncer) Plan(ctx context.Context) (*Plan, error) {
+	from, err := s.list(ctx, s.From)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.list(ctx, s.To)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{}
+	linked := map[string]map[string]bool{s.From.Name: {}, s.To.Name: {}}
+
+	for _, link := range s.State.Links {
+		fromItem, ok1 := link.Items[s.From.Name]
+		toItem, ok2 := link.Items[s.To.Name]
+		if !ok1 || !ok2 {
+			continue
+		}
+		linked[s.From.Name][fromItem.ID] = true
+		linked[s.To.Name][toItem.ID] = true
+
+		if link.DeletedIn != "" {
+			action, err := s.planTombstone(ctx, link, from, to)
+			if err != nil {
+				return nil, err
+			}
+			if action != nil {
+				plan.Actions = append(plan.Actions, action)
+			}
+			continue
+		}
+
+		fr, err := s.find(ctx, s.From, from, fromItem.ID)
+		if err != nil {
+			return nil, err
+		}
+		tr, err := s.find(ctx, s.To, to, toItem.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if action := s.planLink(link, fr, tr, fromItem, toItem); action != nil {
+			plan.Actions = append(plan.Actions, action)
+		}
+	}
+
+	plan.Actions = append(plan.Actions, s.planCreates(s.From, s.To, from, linked[s.From.Name])...)
+	if s.Both {
+		plan.Actions = append(plan.Actions, s.planCreates(s.To, s.From, to, linked[s.To.Name])...)
+	}
+
+	return plan, nil
+}
+
+func (s *Syncer) planLink(link *Link, fr, tr *protocol.Reminder, fromItem, toItem Item) *Action {
+	switch {
+	case fr == nil && tr == nil:
+		return &Action{Kind: ActionUnlink, From: s.From.Name, To: s.To.Name, FromID: fromItem.ID, ToID: toItem.ID,
+			Reason: "deleted in both backends", link: link}
+	case fr == nil:
+		return &Action{Kind: ActionUnlink, From: s.From.Name, To: s.To.Name, Title: tr.Title, FromID: fromItem.ID, ToID: toItem.ID,
+			Reason: "deleted in " + s.From.Name, link: link, deletedIn: s.From.Name}
+	case tr == nil:
+		return &Action{Kind: ActionUnlink, From: s.From.Name, To: s.To.Name, Title: fr.Title, FromID: fromItem.ID, ToID: toItem.ID,
+			Reason: "deleted in " + s.To.Name, link: link, deletedIn: s.To.Name}
+	}
+
+	fromChanged := fingerprint(fr) != fromItem.Hash
+	toChanged := fingerprint(tr) != toItem.Hash
+
+	switch {
+	case fromChanged && toChanged:
+		return &Action{Kind: ActionConflict, From: s.From.Name, To: s.To.Name, Title: fr.Title, FromID: fr.ID, ToID: tr.ID,
+			Reason: "changed in both backends since the last sync", link: link}
+	case fromChanged:
+		return changeAction(s.From, s.To, fr, tr, link)
+	case toChanged && s.Both:
+		return changeAction(s.To, s.From, tr, fr, link)
+	}
+	return nil
+}
+
+// planTombstone drops a tombstone once the reminder left behind is gone
+// as well; until then it is not synced.
+func (s *Syncer) planTombstone(ctx context.Context, link *Link, from, to []*protocol.Reminder) (*Action, error) {
+	survivor, listed := s.To, to
+	if link.DeletedIn == s.To.Name {
+		survivor, listed = s.From, from
+	}
+
+	r, err := s.find(ctx, survivor, listed, link.Items[survivor.Name].ID)
+	if err != nil || r != nil {
+		return nil, err
+	}
+	return &Action{Kind: ActionUnlink, From: s.From.Name, To: s.To.Name,
+		FromID: link.Items[s.From.Name].ID, ToID: link.Items[s.To.Name].ID,
+		Reason: "deleted in both backends", link: link}, nil
+}
+
+func changeAction(from, to Endpoint, source, target *protocol.Reminder, link *Link) *Action {
+	kind := ActionUpdate
+	if source.Completed && !target.Completed {
+		kind = ActionComplete
+	}
+	return &Action{Kind: kind, From: from.Name, To: to.Name, Title: source.Title, FromID: source.ID, ToID: target.ID,
+		from: from, to: to, source: source, target: target, link: link}
+}
+
+// planCreates returns create actions for open reminders in from that are
+// not linked yet, parents before their subtasks.
+func (s *Syncer) planCreates(from, to Endpoint, reminders []*protocol.Reminder, linked map[string]bool) []*Action {
+	var pending []*protocol.Reminder
+	for _, r := range reminders {
+		if !linked[r.ID] && !r.Completed {
+			pending = append(pending, r)
+		}
+	}
+	sort.SliceStable(pending, func(i, j int) bool {
+		return pending[i].ParentID == "" && pending[j].ParentID != ""
+	})
+
+	actions := make([]*Action, 0, len(pending))
+	for _, r := range pending {
+		actions = append(actions, &Action{Kind: ActionCreate, From: from.Name, To: to.Name, Title: r.Title, FromID: r.ID,
+			from: from, to: to, source: r})
+	}
+	return actions
+}
+
+// Apply performs the plan's actions and saves the state. Conflicts are
+// skipped. A failed action records its error and does not stop the rest;
+// check Plan.Failed afterwards.
+func (s *Syncer) Apply(ctx context.Context, plan *Plan) error {
+	for _, a := range plan.Actions {
+		var err error
+		switch a.Kind {
+		case ActionCreate:
+			err = s.create(ctx, a)
+		case ActionUpdate, ActionComplete:
+			err = s.update(ctx, a)
+		case ActionUnlink:
+			if a.deletedIn != "" {
+				a.link.DeletedIn = a.deletedIn
+			} else {
+				s.State.remove(a.link)
+			}
+		}
+		if err != nil {
+			a.Error = err.Error()
+		}
+	}
+
+	return s.State.Save()
+}
+
+func (s *Syncer) create(ctx context.Context, a *Action) error {
+	r := protocol.NewReminder(a.source.Title)
+	copyFields(r, a.source)
+	if a.source.ParentID != "" {
+		if link := s.State.Lookup(a.from.Name, a.source.ParentID); link != nil {
+			r.ParentID = link.Items[a.to.Name].ID
+			r.IsSubtask = true
+		}
+	}
+
+	if err := a.to.Store.Add(ctx, r); err != nil {
+		return fmt.Errorf("creating in %s: %w", a.to.Name, err)
+	}
+	a.ToID = r.ID
+
+	link := &Link{}
+	s.State.Links = append(s.State.Links, link)
+	s.record(ctx, link, a.from, a.source, a.to, r)
+	return nil
+}
+
+func (s *Syncer) update(ctx context.Context, a *Action) error {
+	target := a.target
+	copyFields(target, a.source)
+	target.UpdatedAt = s.now()
+	if !a.source.Completed && target.Completed {
+		// Reopened since the last sync.
+		target.Completed = false
+		target.CompletedAt = nil
+	}
+
+	if err := a.to.Store.Update(ctx, target); err != nil {
+		return fmt.Errorf("updating in %s: %w", a.to.Name, err)
+	}
+	if a.Kind == ActionComplete {
+		if err := a.to.Store.Complete(ctx, target.ID); err != nil {
+			return fmt.Errorf("completing in %s: %w", a.to.Name, err)
+		}
+	}
+
+	s.record(ctx, a.link, a.from, a.source, a.to, target)
+	return nil
+}
+
+// record stores both sides of a link as the backends now hold them. The
+// written side is read back so backend normalization does not show up as
+// a change on the next run.
+func (s *Syncer) record(ctx context.Context, link *Link, from Endpoint, source *protocol.Reminder, to Endpoint, written *protocol.Reminder) {
+	if fresh, err := to.Store.Get(ctx, written.ID); err == nil {
+		written = fresh
+	}
+
+	link.Items = map[string]Item{
+		from.Name: itemFor(source),
+		to.Name:   itemFor(written),
+	}
+	link.SyncedAt = s.now()
+}
+
+func (s *Syncer) list(ctx context.Context, e Endpoint) ([]*protocol.Reminder, error) {
+	reminders, err := e.Store.List(ctx, &protocol.ListFilter{IncludeCompleted: true})
+	if err != nil {
+		return nil, fmt.Errorf("listing %s: %w", e.Name, err)
+	}
+	return reminders, nil
+}
+
+// find returns the reminder with id from the listing, falling back to Get
+// for backends that leave completed reminders out of List. A nil result
+// means the reminder no longer exists.
+func (s *Syncer) find(ctx context.Context, e Endpoint, listed []*protocol.Reminder, id string) (*protocol.Reminder, error) {
+	for _, r := range listed {
+		if r.ID == id {
+			return r, nil
+		}
+	}
+
+	r, err := e.Store.Get(ctx, id)
+	if errors.Is(err, protocol.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting %s from %s: %w", id, e.Name, err)
+	}
+	return r, nil
+}
+
+func (s *Syncer) now() time.Time {
+	if s.Now != nil {
+		return s.Now()
+	}
+	return time.Now()
+}
+
+// copyFields copies the synced fields of src onto dst. IDs, parents,
+// completion and backend metadata are left to the caller.
+func copyFields(dst, src *protocol.Reminder) {
+	dst.Title = src.Title
+	dst.Notes = src.Notes
+	dst.Due = src.Due
+	dst.Priority = src.Priority
+	dst.Tags = append([]string(nil), src.Tags...)
+	dst.Links = append([]string(nil), src.Links...)
+	dst.Recurrence = src.Recurrence
+}
+
+func itemFor(r *protocol.Reminder) Item {
+	return Item{ID: r.ID, UpdatedAt: r.UpdatedAt, Hash: fingerprint(r)}
+}
+
+// fingerprint hashes the fields that sync copies, plus completion.
+func fingerprint(r *protocol.Reminder) string {
+	tags := append([]string(nil), r.Tags...)
+	sort.Strings(tags)
+
+	var due, rule string
+	if r.Due != nil {
+		due = r.Due.UTC().Format(time.RFC3339)
+	}
+	if r.Recurrence != nil {
+		rule = r.Recurrence.String()
+	}
+
+	data, _ := json.Marshal([]any{r.Title, r.Notes, due, r.Priority, tags, r.Links, rule, r.Completed})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:12])
+}
@@ -0,0 +1,301 @@
+package syncer
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/shaneoxm/recall/internal/adapters/jsonl"
+	"github.com/shaneoxm/recall/internal/protocol"
+)
+
+type fixture struct {
+	dir      string
+	from, to *jsonl.Store
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+	dir := t.TempDir()
+
+	from, err := jsonl.New(filepath.Join(dir, "from.jsonl"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	to, err := jsonl.New(filepath.Join(dir, "to.jsonl"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	return &fixture{dir: dir, from: from, to: to}
+}
+
+// syncer loads the saved state fresh, as separate rc runs would.
+func (f *fixture) syncer(t *testing.T, both bool) *Syncer {
+	t.Helper()
+	state, err := LoadState(StatePath(f.dir, "from", "to"))
+	if err != nil {
+		t.Fatalf("failed to load state: %v", err)
+	}
+	return &Syncer{
+		From:  Endpoint{Name: "from", Store: f.from},
+		To:    Endpoint{Name: "to", Store: f.to},
+		State: state,
+		Both:  both,
+	}
+}
+
+func (f *fixture) run(t *testing.T, both bool) *Plan {
+	t.Helper()
+	ctx := context.Background()
+
+	s := f.syncer(t, both)
+	plan, err := s.Plan(ctx)
+	if err != nil {
+		t.Fatalf("plan failed: %v", err)
+	}
+	if err := s.Apply(ctx, plan); err != nil {
+		t.Fatalf("apply failed: %v", err)
+	}
+	if failed := plan.Failed(); len(failed) > 0 {
+		t.Fatalf("actions failed: %s", failed[0].Error)
+	}
+	return plan
+}
+
+func list(t *testing.T, s protocol.Store) []*protocol.Reminder {
+	t.Helper()
+	reminders, err := s.List(context.Background(), &protocol.ListFilter{IncludeCompleted: true})
+	if err != nil {
+		t.Fatalf("failed to list: %v", err)
+	}
+	return reminders
+}
+
+func TestSyncer_CreatesThenIdle(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	parent := protocol.NewReminder("Plan trip")
+	parent.AddTag("travel")
+	child := protocol.NewReminder("Book flights")
+	child.ParentID = parent.ID
+	child.IsSubtask = true
+	done := protocol.NewReminder("Already done")
+	done.Complete()
+
+	// Add the child first to check parents are created before subtasks.
+	for _, r := range []*protocol.Reminder{child, parent, done} {
+		if err := f.from.Add(ctx, r); err != nil {
+			t.Fatalf("failed to add: %v", err)
+		}
+	}
+
+	plan := f.run(t, false)
+	if got := plan.Count(ActionCreate); got != 2 {
+		t.Fatalf("expected 2 creates, got %d", got)
+	}
+
+	copied := list(t, f.to)
+	if len(copied) != 2 {
+		t.Fatalf("expected 2 reminders in target, got %d", len(copied))
+	}
+	var copiedParent, copiedChild *protocol.Reminder
+	for _, r := range copied {
+		switch r.Title {
+		case "Plan trip":
+			copiedParent = r
+		case "Book flights":
+			copiedChild = r
+		}
+	}
+	if copiedParent == nil || copiedChild == nil {
+		t.Fatalf("expected parent and child in target, got %v", copied)
+	}
+	if copiedChild.ParentID != copiedParent.ID {
+		t.Errorf("expected child parent %q, got %q", copiedParent.ID, copiedChild.ParentID)
+	}
+	if len(copiedParent.Tags) != 1 || copiedParent.Tags[0] != "travel" {
+		t.Errorf("expected tags to be copied, got %v", copiedParent.Tags)
+	}
+
+	if plan := f.run(t, false); len(plan.Actions) != 0 {
+		t.Errorf("expected no actions on second run, got %d", len(plan.Actions))
+	}
+}
+
+func TestSyncer_UpdateAndComplete(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	r := protocol.NewReminder("Call mom")
+	f.from.Add(ctx, r)
+	f.run(t, false)
+
+	r.SetNotes("Birthday next week")
+	f.from.Update(ctx, r)
+
+	plan := f.run(t, false)
+	if got := plan.Count(ActionUpdate); got != 1 {
+		t.Fatalf("expected 1 update, got %d", got)
+	}
+	target := list(t, f.to)[0]
+	if target.Notes != "Birthday next week" {
+		t.Errorf("expected notes to be updated, got %q", target.Notes)
+	}
+
+	f.from.Complete(ctx, r.ID)
+	plan = f.run(t, false)
+	if got := plan.Count(ActionComplete); got != 1 {
+		t.Fatalf("expected 1 complete, got %d", got)
+	}
+	if target := list(t, f.to)[0]; !target.Completed {
+		t.Error("expected target to be completed")
+	}
+}
+
+func TestSyncer_Reopen(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	r := protocol.NewReminder("Call mom")
+	f.from.Add(ctx, r)
+	f.run(t, false)
+	f.from.Complete(ctx, r.ID)
+	f.run(t, false)
+
+	r, _ = f.from.Get(ctx, r.ID)
+	r.Completed, r.CompletedAt = false, nil
+	f.from.Update(ctx, r)
+
+	plan := f.run(t, false)
+	if got := plan.Count(ActionUpdate); got != 1 {
+		t.Fatalf("expected 1 update, got %+v", plan.Actions)
+	}
+	if target := list(t, f.to)[0]; target.Completed || target.CompletedAt != nil {
+		t.Errorf("expected target to be reopened, got %+v", target)
+	}
+	if plan := f.run(t, false); len(plan.Actions) != 0 {
+		t.Errorf("expected no actions after reopening, got %+v", plan.Actions)
+	}
+}
+
+func TestSyncer_Conflict(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	r := protocol.NewReminder("Review PR")
+	f.from.Add(ctx, r)
+	f.run(t, false)
+
+	r.SetNotes("from side")
+	f.from.Update(ctx, r)
+
+	target := list(t, f.to)[0]
+	target.SetNotes("to side")
+	f.to.Update(ctx, target)
+
+	plan := f.run(t, false)
+	if got := plan.Count(ActionConflict); got != 1 {
+		t.Fatalf("expected 1 conflict, got %d", got)
+	}
+	if got := list(t, f.to)[0].Notes; got != "to side" {
+		t.Errorf("expected conflicting target to be left alone, got %q", got)
+	}
+}
+
+func TestSyncer_Both(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	r := protocol.NewReminder("Water plants")
+	f.from.Add(ctx, r)
+	f.run(t, true)
+
+	target := list(t, f.to)[0]
+	target.SetPriority(3)
+	f.to.Update(ctx, target)
+	f.to.Add(ctx, protocol.NewReminder("Added remotely"))
+
+	// One-way sync ignores changes made on the target.
+	if plan := f.run(t, false); len(plan.Actions) != 0 {
+		t.Fatalf("expected no one-way actions, got %d", len(plan.Actions))
+	}
+
+	plan := f.run(t, true)
+	if plan.Count(ActionUpdate) != 1 || plan.Count(ActionCreate) != 1 {
+		t.Fatalf("expected 1 update and 1 create, got %+v", plan.Actions)
+	}
+
+	got, err := f.from.Get(ctx, r.ID)
+	if err != nil {
+		t.Fatalf("failed to get: %v", err)
+	}
+	if got.Priority != 3 {
+		t.Errorf("expected priority to flow back, got %d", got.Priority)
+	}
+	if n := len(list(t, f.from)); n != 2 {
+		t.Errorf("expected 2 reminders in source, got %d", n)
+	}
+}
+
+func TestSyncer_DryRunAndUnlink(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	r := protocol.NewReminder("Renew passport")
+	f.from.Add(ctx, r)
+
+	plan, err := f.syncer(t, false).Plan(ctx)
+	if err != nil {
+		t.Fatalf("plan failed: %v", err)
+	}
+	if plan.Count(ActionCreate) != 1 || len(list(t, f.to)) != 0 {
+		t.Fatal("expected a planned create and no writes")
+	}
+
+	f.run(t, false)
+	f.to.Delete(ctx, list(t, f.to)[0].ID)
+
+	plan = f.run(t, false)
+	if got := plan.Count(ActionUnlink); got != 1 {
+		t.Fatalf("expected 1 unlink, got %d", got)
+	}
+	links := f.syncer(t, false).State.Links
+	if len(links) != 1 || links[0].DeletedIn != "to" {
+		t.Fatalf("expected a tombstone for the deleted reminder, got %+v", links)
+	}
+
+	// The reminder left in from is not copied again.
+	if plan := f.run(t, false); len(plan.Actions) != 0 {
+		t.Errorf("expected no actions after the unlink, got %+v", plan.Actions)
+	}
+
+	// Once it is deleted there too, the tombstone goes.
+	f.from.Delete(ctx, r.ID)
+	if plan := f.run(t, false); plan.Count(ActionUnlink) != 1 {
+		t.Fatalf("expected the tombstone to be dropped, got %+v", plan.Actions)
+	}
+	if n := len(f.syncer(t, false).State.Links); n != 0 {
+		t.Errorf("expected link to be removed, got %d", n)
+	}
+}
+
+func TestSyncer_BothKeepsDeletions(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	r := protocol.NewReminder("Renew passport")
+	f.from.Add(ctx, r)
+	f.run(t, true)
+	f.from.Delete(ctx, r.ID)
+
+	if plan := f.run(t, true); plan.Count(ActionUnlink) != 1 {
+		t.Fatalf("expected 1 unlink, got %+v", plan.Actions)
+	}
+	if plan := f.run(t, true); len(plan.Actions) != 0 {
+		t.Errorf("expected the deleted reminder not to be re-created, got %+v", plan.Actions)
+	}
+	if n := len(list(t, f.from)); n != 0 {
+		t.Errorf("expected no reminders in source, got %d", n)
+	}
+}